@@ -0,0 +1,65 @@
+package acidtab
+
+// BarCol prints the numbers in column n as horizontal bars, which are width
+// columns wide for the highest value in the column.
+//
+// This uses the original values as passed to Row(), and works for all integer
+// and float types; other values are printed as usual. This sets the alignment
+// to Left; use AlignCol() after BarCol() to change it. Use 0 to disable it.
+//
+// This only applies to Horizontal(), Vertical(), and HTML(); also see
+// FormatAsBar() to print bars with a fixed maximum.
+func (t *Table) BarCol(n, width int) *Table {
+	if t.checkN(n, "BarCol") {
+		t.bars[n] = width
+		if width > 0 {
+			t.align[n] = Left
+		}
+	}
+	return t
+}
+
+// barRows gets the rows with the cells in BarCol() columns replaced by bars,
+// and the widths with the width for these columns recalculated.
+func (t Table) barRows() ([][]string, []int) {
+	var cols []int
+	for i, w := range t.bars {
+		/// Ignore columns removed by a later Header().
+		if w > 0 && i < len(t.header) {
+			cols = append(cols, i)
+		}
+	}
+	if len(cols) == 0 {
+		return t.rows, t.widths
+	}
+
+	rows := make([][]string, len(t.rows))
+	for i := range t.rows {
+		rows[i] = append(make([]string, 0, len(t.rows[i])), t.rows[i]...)
+	}
+	widths := append(make([]int, 0, len(t.widths)), t.widths...)
+	for _, c := range cols {
+		var max float64
+		for _, r := range t.raw {
+			if c < len(r) {
+				if f, ok := toFloat(r[c]); ok && f > max {
+					max = f
+				}
+			}
+		}
+
+		widths[c] = textWidth(t.header[c])
+		for i, r := range t.raw {
+			if c >= len(r) {
+				continue
+			}
+			if f, ok := toFloat(r[c]); ok {
+				rows[i][c] = barText(f, max, t.bars[c])
+			}
+			if l := t.cellWidth(c, rows[i][c]); l > widths[c] {
+				widths[c] = l
+			}
+		}
+	}
+	return rows, widths
+}
@@ -0,0 +1,59 @@
+package acidtab
+
+import "testing"
+
+func TestBarCol(t *testing.T) {
+	tbl := New("Disk", "Used", "Bar").
+		FormatColFunc(1, FormatAsNum()).
+		Rows(
+			"/", 40, 40,
+			"/home", 100, 100,
+			"/tmp", 3.5, 3.5,
+			"/mnt", "?", "?").
+		BarCol(2, 10)
+
+	test(t, tbl.Horizontal, `
+		  Disk   │  Used  │     Bar
+		─────────┼────────┼──────────────
+		  /      │    40  │  ████
+		  /home  │   100  │  ██████████
+		  /tmp   │     4  │  ▍
+		  /mnt   │     ?  │  ?
+	`)
+
+	test(t, tbl.Vertical, `
+		Disk  │  /
+		Used  │  40
+		Bar   │  ████
+		──────┼────────────
+		Disk  │  /home
+		Used  │  100
+		Bar   │  ██████████
+		──────┼────────────
+		Disk  │  /tmp
+		Used  │  4
+		Bar   │  ▍
+		──────┼────────────
+		Disk  │  /mnt
+		Used  │  ?
+		Bar   │  ?
+	`)
+
+	tbl.BarCol(2, 0).AlignCol(2, Right)
+	test(t, tbl.Horizontal, `
+		  Disk   │  Used  │  Bar
+		─────────┼────────┼───────
+		  /      │    40  │   40
+		  /home  │   100  │  100
+		  /tmp   │     4  │  3.5
+		  /mnt   │     ?  │    ?
+	`)
+
+	tbl = New("a", "b").Rows("x", 1, "y", 2).BarCol(1, 4).Header(true, "a")
+	test(t, tbl.Horizontal, `
+		  a
+		─────
+		  x  │
+		  y  │
+	`)
+}
@@ -0,0 +1,57 @@
+package acidtab
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSV writes the table as comma-separated values.
+//
+// The header is written as the first line if it's enabled with Header(). The
+// cells are written as formatted with FormatCol() and FormatColFunc(); use
+// StripEscapes() to remove any terminal escape sequences.
+func (t Table) CSV(w io.Writer) error {
+	return t.delimited(w, ',')
+}
+
+// TSV writes the table as tab-separated values.
+//
+// This is identical to CSV(), except that the fields are separated by tabs.
+func (t Table) TSV(w io.Writer) error {
+	return t.delimited(w, '\t')
+}
+
+func (t Table) delimited(w io.Writer, delim rune) error {
+	c := csv.NewWriter(w)
+	c.Comma = delim
+
+	if t.pHeader {
+		if err := c.Write(t.exportRow(t.header)); err != nil {
+			return err
+		}
+	}
+	for _, r := range t.rows {
+		if err := c.Write(t.exportRow(r)); err != nil {
+			return err
+		}
+	}
+
+	c.Flush()
+	return c.Error()
+}
+
+// exportRow makes sure the row has exactly the number of header columns, and
+// strips escapes if set.
+func (t Table) exportRow(r []string) []string {
+	row := make([]string, len(t.header))
+	for i := range r {
+		if i > len(t.header)-1 {
+			break
+		}
+		row[i] = r[i]
+		if t.stripEsc {
+			row[i] = stripEscapes(row[i])
+		}
+	}
+	return row
+}
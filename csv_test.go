@@ -0,0 +1,38 @@
+package acidtab
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	red := func(s string) string { return "\x1b[31m" + s + "\x1b[0m" }
+	tbl := New("Name", "Count", "Note").
+		FormatColFunc(1, FormatAsNum()).
+		Rows(
+			"a,b", 1234, red("x"),
+			`"quoted"`, 1, "tab\there",
+		).
+		Row("short")
+
+	test(t, noErr(t, tbl.CSV), `
+		Name,Count,Note
+		"a,b","1,234",`+"\x1b[31mx\x1b[0m"+`
+		"""quoted""",1,tab	here
+		short,,
+	`)
+
+	tbl.StripEscapes(true).Header(false)
+	test(t, noErr(t, tbl.CSV), `
+		"a,b","1,234",x
+		"""quoted""",1,tab	here
+		short,,
+	`)
+	// Can't use test() as that trims the trailing tabs.
+	have := new(bytes.Buffer)
+	noErr(t, tbl.TSV)(have)
+	want := "a,b\t1,234\tx\n" + `"""quoted"""` + "\t1\t\"tab\there\"\n" + "short\t\t\n"
+	if have.String() != want {
+		t.Errorf("\nwant: %q\nhave: %q", want, have.String())
+	}
+}
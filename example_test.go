@@ -20,6 +20,7 @@ func TestShowExamples(t *testing.T) {
 		Example_chain,
 		Example_format,
 		Example_stringRows,
+		Example_markdown,
 	} {
 		continue
 		fmt.Println("=> " +
@@ -240,3 +241,17 @@ func Example_stringRows() {
 	// │  Fred Johnson        │  Earth 🌎      │  Colonol 🎖      │  Beltalowda              │  false  │
 	// └──────────────────────┴────────────────┴─────────────────┴──────────────────────────┴─────────┘
 }
+
+func Example_markdown() {
+	t := acidtab.New("Name", "Origin", "Job")
+	t.AlignCol(2, acidtab.Center)
+	t.Row("Prax Meng", "Ganymede", "Botanist")
+	t.Row("Klaes Ashford", "The belt", "Pirate")
+	t.Markdown(os.Stdout)
+
+	// Output:
+	// | Name          | Origin   |   Job    |
+	// | :------------ | :------- | :------: |
+	// | Prax Meng     | Ganymede | Botanist |
+	// | Klaes Ashford | The belt |  Pirate  |
+}
@@ -0,0 +1,190 @@
+package acidtab
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Aggregate is a value calculated from all the values in a column.
+type Aggregate uint8
+
+// Aggregates that can be used in Footer().
+const (
+	_             Aggregate = iota
+	Sum                     // Sum of all numbers.
+	Avg                     // Average of all numbers, as a float64.
+	Min                     // Lowest value.
+	Max                     // Highest value.
+	Count                   // Number of values.
+	CountDistinct           // Number of distinct values.
+)
+
+// Footer adds a footer row, which is printed below all the other rows after a
+// separator line.
+//
+// The cells can be an Aggregate, which is calculated from the original values
+// in that column when the table is printed; for example:
+//
+//	t.Footer("Total", acidtab.Sum, acidtab.Avg)
+//
+// Aggregates and other non-string values are formatted with FormatCol() and
+// FormatColFunc(), just like regular cells. Strings are always printed as-is,
+// so you can add labels such as "Total" to any column.
+//
+// Min and Max have the same type as the column values if they all have the
+// same type (e.g. int or time.Duration). Sum does too for 64-bit integer and
+// float types; it's an int64 or uint64 for smaller integers, and a float64 if
+// the values have different types or the integer sum overflows. Avg is always a
+// float64, and Count and CountDistinct are an int.
+//
+// Footer can be called more than once to add multiple footer rows. Footers are
+// only printed by Horizontal() and Vertical().
+func (t *Table) Footer(cells ...any) *Table {
+	if len(cells) > len(t.header) {
+		t.err = fmt.Errorf(
+			"Footer: adding footer %d: too many values (%d); there are only %d headers",
+			len(t.footer), len(cells), len(t.header))
+		return t
+	}
+	t.footer = append(t.footer, append(make([]any, 0, len(cells)), cells...))
+	return t
+}
+
+// footerRows gets the formatted footer rows, including the grand total for
+// GroupBy().
+func (t Table) footerRows() [][]string {
+	total := t.grouped && len(t.groupAggs) > 0
+	if len(t.footer) == 0 && !total {
+		return nil
+	}
+	rows := make([][]string, 0, len(t.footer)+1)
+	if total {
+		rows = append(rows, t.formatAggregates(t.groupCells(), t.raw))
+	}
+	for _, f := range t.footer {
+		rows = append(rows, t.formatAggregates(f, t.raw))
+	}
+	return rows
+}
+
+// formatAggregates formats cells, calculating any Aggregate from raw.
+func (t Table) formatAggregates(cells []any, raw [][]any) []string {
+	row := make([]string, len(t.header))
+	for i, c := range cells {
+		switch cc := c.(type) {
+		case string:
+			row[i] = cc
+		case Aggregate:
+			if v := cc.calc(i, raw); v != nil {
+				row[i] = t.formatCell(i, v)
+			}
+		default:
+			row[i] = t.formatCell(i, c)
+		}
+	}
+	return row
+}
+
+// calc calculates the aggregate for column col; this returns nil if there are
+// no values to calculate it from.
+func (a Aggregate) calc(col int, raw [][]any) any {
+	vals := make([]any, 0, len(raw))
+	for _, r := range raw {
+		if col < len(r) && r[col] != nil {
+			vals = append(vals, r[col])
+		}
+	}
+
+	switch a {
+	case Count:
+		return len(vals)
+	case CountDistinct:
+		seen := make(map[any]struct{}, len(vals))
+		for _, v := range vals {
+			seen[distinctKey(v)] = struct{}{}
+		}
+		return len(seen)
+	case Min, Max:
+		if len(vals) == 0 {
+			return nil
+		}
+		m := vals[0]
+		for _, v := range vals[1:] {
+			c := compare(v, m, SortAuto)
+			if (a == Min && c < 0) || (a == Max && c > 0) {
+				m = v
+			}
+		}
+		return m
+	case Sum, Avg:
+		/// Integers are added as int64 or uint64, so that large sums don't lose
+		/// precision; the float64 sum is used if there are floats or strings,
+		/// or if the integer sum overflows.
+		var (
+			sum          float64
+			isum         int64
+			usum         uint64
+			n, ints, uns int
+			overflow     bool
+			typ          reflect.Type // Type of all values, or nil if they're not the same.
+		)
+		for _, v := range vals {
+			switch rv := reflect.ValueOf(v); rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				i := rv.Int()
+				if r := isum + i; (i > 0 && r < isum) || (i < 0 && r > isum) {
+					overflow = true
+				}
+				isum += i
+				sum += float64(i)
+				ints++
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				u := rv.Uint()
+				if usum+u < usum {
+					overflow = true
+				}
+				usum += u
+				sum += float64(u)
+				uns++
+			default:
+				f, ok := parseFloat(v)
+				if !ok {
+					continue
+				}
+				sum += f
+			}
+			n++
+			if n == 1 {
+				typ = reflect.TypeOf(v)
+			} else if reflect.TypeOf(v) != typ {
+				typ = nil
+			}
+		}
+		if n == 0 {
+			return nil
+		}
+		if a == Avg {
+			return sum / float64(n)
+		}
+
+		/// Integer sums are only converted to the column type if it's 64 bits,
+		/// as the sum of smaller types may not fit.
+		var (
+			total any = sum
+			conv      = typ != nil && n == len(vals)
+		)
+		switch {
+		case n == ints && !overflow:
+			total, conv = isum, conv && typ.Size() == 8
+		case n == uns && !overflow:
+			total, conv = usum, conv && typ.Size() == 8
+		default:
+			conv = conv && (typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64)
+		}
+		if conv {
+			return reflect.ValueOf(total).Convert(typ).Interface()
+		}
+		return total
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package acidtab
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFooter(t *testing.T) {
+	tbl := New("Name", "N", "F", "Time", "Host").Close(CloseAll).
+		FormatColFunc(1, FormatAsNum()).
+		Rows(
+			"a", 1000, 1.5, time.Second, "x",
+			"b", 20, 2.5, 2*time.Second, "y",
+			"c", 3, 2.0, time.Minute, "x",
+		).
+		Footer("Total", Sum, Sum, Sum, CountDistinct).
+		Footer("", Avg, Min, Max, Count)
+	if err := tbl.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	test(t, tbl.Horizontal, `
+		┌─────────┬─────────┬───────┬────────┬────────┐
+		│  Name   │    N    │   F   │  Time  │  Host  │
+		├─────────┼─────────┼───────┼────────┼────────┤
+		│  a      │  1,000  │  1.5  │    1s  │  x     │
+		│  b      │     20  │  2.5  │    2s  │  y     │
+		│  c      │      3  │    2  │  1m0s  │  x     │
+		├─────────┼─────────┼───────┼────────┼────────┤
+		│  Total  │  1,023  │    6  │  1m3s  │  2     │
+		│         │    341  │  1.5  │  1m0s  │  3     │
+		└─────────┴─────────┴───────┴────────┴────────┘
+	`)
+
+	tbl = New("a", "b").Close(CloseAll).
+		Rows("x", 1, "y", 2).
+		SortBy(SortKey{Col: 1, Desc: true}).
+		Footer("", Sum)
+	test(t, tbl.Vertical, `
+		┌─────┬─────┐
+		│  a  │  y  │
+		│  b  │  2  │
+		├─────┼─────┤
+		│  a  │  x  │
+		│  b  │  1  │
+		├─────┼─────┤
+		│  a  │     │
+		│  b  │  3  │
+		└─────┴─────┘
+	`)
+
+	if err := New("a").Footer(1, 2).Error(); !errorContains(err, "too many values") {
+		t.Error(err)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	raw := [][]any{{1, "a", "1.5"}, {2, "b"}, {3, "a", "x"}, {}}
+	tests := []struct {
+		a    Aggregate
+		col  int
+		want any
+	}{
+		{Sum, 0, 6},
+		{Sum, 1, nil},
+		{Sum, 2, 1.5},
+		{Avg, 0, 2.0},
+		{Min, 0, 1},
+		{Max, 0, 3},
+		{Max, 1, "b"},
+		{Min, 3, nil},
+		{Count, 0, 3},
+		{Count, 2, 2},
+		{CountDistinct, 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			if have := tt.a.calc(tt.col, raw); have != tt.want {
+				t.Errorf("%d for col %d:\nhave: %#v\nwant: %#v", tt.a, tt.col, have, tt.want)
+			}
+		})
+	}
+
+	big := [][]any{
+		{int64(1 << 53), uint64(1 << 63), 1 << 53, 1.5,
+			int64(math.MaxInt64), uint64(math.MaxUint64), int8(100), uint8(200), time.Second},
+		{int64(1), uint64(1), int64(1), 1,
+			int64(1), uint64(1), int8(100), uint8(100), time.Second},
+	}
+	sums := []struct {
+		col  int
+		want any
+	}{
+		{0, int64(1<<53 + 1)},
+		{1, uint64(1<<63 + 1)},
+		{2, int64(1<<53 + 1)},
+		{3, 2.5},
+		{4, float64(math.MaxInt64) + 1},
+		{5, float64(math.MaxUint64) + 1},
+		{6, int64(200)},
+		{7, uint64(300)},
+		{8, 2 * time.Second},
+	}
+	for _, tt := range sums {
+		t.Run("", func(t *testing.T) {
+			if have := Sum.calc(tt.col, big); have != tt.want {
+				t.Errorf("col %d:\nhave: %#v\nwant: %#v", tt.col, have, tt.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,9 @@ module zgo.at/acidtab
 
 go 1.19
 
-require zgo.at/termtext v1.5.0
-
 require (
-	github.com/rivo/uniseg v0.4.7 // indirect
-	zgo.at/runewidth v0.1.0 // indirect
+	github.com/rivo/uniseg v0.4.7
+	zgo.at/termtext v1.5.0
 )
+
+require zgo.at/runewidth v0.1.0 // indirect
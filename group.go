@@ -0,0 +1,131 @@
+package acidtab
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GroupBy groups rows with the same value in column col.
+//
+// Groups are printed in the order the first row of every group was added, and
+// rows in a group keep their order. A line is printed between groups.
+//
+// The aggs are aggregates for every column (starting at 0), which are printed
+// as a subtotal row after every group and a grand total row in the footer; use
+// 0 to not calculate anything for a column. The subtotal rows show the group
+// value in column col, unless there's an aggregate for it. No subtotals or
+// total are printed if aggs is empty. For example:
+//
+//	t.GroupBy(0, 0, acidtab.Count, acidtab.Sum)
+//
+// This happens when the table is printed, and only applies to Horizontal() and
+// Vertical(). Separator() is ignored for grouped tables.
+func (t *Table) GroupBy(col int, aggs ...Aggregate) *Table {
+	if !t.checkN(col, "GroupBy") {
+		return t
+	}
+	if len(aggs) > len(t.header) {
+		t.err = fmt.Errorf(
+			"GroupBy: too many aggregates (%d); there are only %d headers",
+			len(aggs), len(t.header))
+		return t
+	}
+	t.grouped, t.groupCol, t.groupAggs = true, col, aggs
+	return t
+}
+
+// HideGroupRepeats sets if the value in the GroupBy() column is only printed
+// for the first row of every group. The default is to print it for every row.
+func (t *Table) HideGroupRepeats(hide bool) *Table { t.groupHide = hide; return t }
+
+// bodyRows gets all the rows to print and which rows should have a line
+// before them, including the subtotals for GroupBy(). If all is set there is a
+// line between every row.
+//
+// The index of every row in t.rows is in idx, or -1 for subtotals.
+func (t Table) bodyRows(all bool) (rows [][]string, lines []bool, idx []int) {
+	if !t.grouped {
+		idx = make([]int, len(t.rows))
+		for i := range idx {
+			idx[i] = i
+		}
+		return t.rows, t.rowLines(all), idx
+	}
+
+	groups := t.groups()
+	rows = make([][]string, 0, len(t.rows)+len(groups))
+	lines = make([]bool, 0, len(t.rows)+len(groups))
+	idx = make([]int, 0, len(t.rows)+len(groups))
+	for _, g := range groups {
+		for j, i := range g {
+			r := t.rows[i]
+			if j > 0 && t.groupHide && t.groupCol < len(r) {
+				r = append(make([]string, 0, len(r)), r...)
+				r[t.groupCol] = ""
+			}
+			rows = append(rows, r)
+			lines = append(lines, len(rows) > 1 && (j == 0 || all))
+			idx = append(idx, i)
+		}
+		if len(t.groupAggs) > 0 {
+			rows = append(rows, t.subtotal(g))
+			lines = append(lines, true)
+			idx = append(idx, -1)
+		}
+	}
+	return rows, lines, idx
+}
+
+// groups gets the row indexes for every group.
+func (t Table) groups() [][]int {
+	var (
+		groups [][]int
+		index  = make(map[any]int)
+	)
+	for i, r := range t.raw {
+		var v any
+		if t.groupCol < len(r) {
+			v = r[t.groupCol]
+		}
+		k := distinctKey(v)
+		g, ok := index[k]
+		if !ok {
+			g = len(groups)
+			index[k] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// subtotal gets the subtotal row for the group with the row indexes g.
+func (t Table) subtotal(g []int) []string {
+	raw := make([][]any, 0, len(g))
+	for _, i := range g {
+		raw = append(raw, t.raw[i])
+	}
+	row := t.formatAggregates(t.groupCells(), raw)
+	if (t.groupCol >= len(t.groupAggs) || t.groupAggs[t.groupCol] == 0) &&
+		t.groupCol < len(t.rows[g[0]]) {
+		row[t.groupCol] = t.rows[g[0]][t.groupCol]
+	}
+	return row
+}
+
+// groupCells gets the GroupBy() aggregates as cells for formatAggregates().
+func (t Table) groupCells() []any {
+	cells := make([]any, 0, len(t.groupAggs))
+	for _, a := range t.groupAggs {
+		cells = append(cells, a)
+	}
+	return cells
+}
+
+// distinctKey gets a value that can be used as a map key for v.
+func distinctKey(v any) any {
+	if v == nil || reflect.TypeOf(v).Comparable() {
+		return v
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,78 @@
+package acidtab
+
+import "testing"
+
+func TestGroupBy(t *testing.T) {
+	tbl := New("Host", "Day", "Requests").
+		Close(CloseLeft|CloseRight).
+		Rows(
+			"a.example.com", "Mon", 42,
+			"b.example.com", "Mon", 7,
+			"a.example.com", "Tue", 13,
+			"b.example.com", "Tue", 21,
+			"c.example.com", "Mon", 1000,
+		)
+
+	test(t, tbl.GroupBy(0).Horizontal, `
+		│      Host       │  Day  │  Requests  │
+		├─────────────────┼───────┼────────────┤
+		│  a.example.com  │  Mon  │        42  │
+		│  a.example.com  │  Tue  │        13  │
+		├─────────────────┼───────┼────────────┤
+		│  b.example.com  │  Mon  │         7  │
+		│  b.example.com  │  Tue  │        21  │
+		├─────────────────┼───────┼────────────┤
+		│  c.example.com  │  Mon  │      1000  │
+	`)
+
+	tbl.GroupBy(0, 0, Count, Sum).HideGroupRepeats(true)
+	test(t, tbl.Horizontal, `
+		│      Host       │  Day  │  Requests  │
+		├─────────────────┼───────┼────────────┤
+		│  a.example.com  │  Mon  │        42  │
+		│                 │  Tue  │        13  │
+		├─────────────────┼───────┼────────────┤
+		│  a.example.com  │  2    │        55  │
+		├─────────────────┼───────┼────────────┤
+		│  b.example.com  │  Mon  │         7  │
+		│                 │  Tue  │        21  │
+		├─────────────────┼───────┼────────────┤
+		│  b.example.com  │  2    │        28  │
+		├─────────────────┼───────┼────────────┤
+		│  c.example.com  │  Mon  │      1000  │
+		├─────────────────┼───────┼────────────┤
+		│  c.example.com  │  1    │      1000  │
+		├─────────────────┼───────┼────────────┤
+		│                 │  5    │      1083  │
+	`)
+
+	tbl = New("Day", "Requests").
+		Close(CloseAll).
+		Rows("Mon", 42, "Tue", 13, "Mon", 7).
+		GroupBy(0, 0, Sum)
+	test(t, tbl.Vertical, `
+		┌────────────┬────────────┐
+		│  Day       │  Mon       │
+		│  Requests  │  42        │
+		├────────────┼────────────┤
+		│  Day       │  Mon       │
+		│  Requests  │  7         │
+		├────────────┼────────────┤
+		│  Day       │  Mon       │
+		│  Requests  │  49        │
+		├────────────┼────────────┤
+		│  Day       │  Tue       │
+		│  Requests  │  13        │
+		├────────────┼────────────┤
+		│  Day       │  Tue       │
+		│  Requests  │  13        │
+		├────────────┼────────────┤
+		│  Day       │            │
+		│  Requests  │  62        │
+		└────────────┴────────────┘
+	`)
+
+	if err := New("a").GroupBy(1).Error(); err == nil {
+		t.Error("no error")
+	}
+}
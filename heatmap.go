@@ -0,0 +1,134 @@
+package acidtab
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+type (
+	// Heatmap colours the numbers in a column on a gradient from green for low
+	// values, to yellow, to red for high values.
+	Heatmap struct {
+		// Range of values; lower and higher values get the colour of Min and
+		// Max. The lowest and highest values in the column are used if both
+		// are 0.
+		Min, Max float64
+
+		Depth   ColorDepth // Colours to use.
+		Reverse bool       // Use red for low values and green for high values.
+	}
+
+	ColorDepth uint8 // Number of colours to use.
+)
+
+// Number of colours to use.
+const (
+	Depth256       ColorDepth = iota // 256-colour palette; the default.
+	Depth16                          // Just green, yellow, and red.
+	DepthTrueColor                   // 24-bit colours.
+)
+
+// HeatmapCol colours the numbers in column n on a gradient.
+//
+// This uses the original values as passed to Row(), and works for the same
+// types that are right-aligned by default: integers, floats, time.Duration, and
+// complex numbers, which use their absolute value. Other values are not
+// coloured. Use a nil h to disable it.
+func (t *Table) HeatmapCol(n int, h *Heatmap) *Table {
+	if t.checkN(n, "HeatmapCol") {
+		t.heatmap[n] = h
+	}
+	return t
+}
+
+// heatRanges gets the range of values for every column with a heatmap.
+func (t Table) heatRanges() [][2]float64 {
+	var ranges [][2]float64
+	for i, h := range t.heatmap {
+		if h == nil {
+			continue
+		}
+		if ranges == nil {
+			ranges = make([][2]float64, len(t.heatmap))
+		}
+		if h.Min != 0 || h.Max != 0 {
+			ranges[i] = [2]float64{h.Min, h.Max}
+			continue
+		}
+
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, r := range t.raw {
+			if i >= len(r) {
+				continue
+			}
+			if f, ok := heatValue(r[i]); ok && !math.IsNaN(f) {
+				lo, hi = math.Min(lo, f), math.Max(hi, f)
+			}
+		}
+		ranges[i] = [2]float64{lo, hi}
+	}
+	return ranges
+}
+
+// heatStyle gets the style for the value v in column col.
+func (t Table) heatStyle(col int, v any) Style {
+	h := t.heatmap[col]
+	if h == nil || col >= len(t.heatRange) {
+		return Style{}
+	}
+	f, ok := heatValue(v)
+	if !ok || math.IsNaN(f) {
+		return Style{}
+	}
+
+	var (
+		lo, hi = t.heatRange[col][0], t.heatRange[col][1]
+		pos    float64 // Position on the gradient, from 0 to 1.
+	)
+	if hi > lo {
+		pos = math.Max(0, math.Min(1, (f-lo)/(hi-lo)))
+	}
+	if h.Reverse {
+		pos = 1 - pos
+	}
+	return Style{Fg: h.Depth.gradient(pos)}
+}
+
+// heatValue gets v as a float64, if it's a number according to isNumber().
+func heatValue(v any) (float64, bool) {
+	if !isNumber(v) {
+		return 0, false
+	}
+	switch c := v.(type) {
+	case complex64:
+		return cmplx.Abs(complex128(c)), true
+	case complex128:
+		return cmplx.Abs(c), true
+	}
+	return toFloat(v)
+}
+
+// gradient gets the colour at pos on the green → yellow → red gradient.
+func (d ColorDepth) gradient(pos float64) Color {
+	if d == Depth16 {
+		switch {
+		case pos < 1.0/3:
+			return ColorGreen
+		case pos < 2.0/3:
+			return ColorYellow
+		}
+		return ColorRed
+	}
+
+	r, g := 1.0, 1.0
+	if pos < .5 {
+		r = pos * 2
+	} else {
+		g = (1 - pos) * 2
+	}
+	if d == DepthTrueColor {
+		return RGB(uint8(math.Round(r*255)), uint8(math.Round(g*255)), 0)
+	}
+	// Colours 16 to 231 are a 6×6×6 colour cube.
+	return Color256(uint8(16 + 36*math.Round(r*5) + 6*math.Round(g*5)))
+}
@@ -0,0 +1,89 @@
+package acidtab
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGradient(t *testing.T) {
+	tests := []struct {
+		depth ColorDepth
+		pos   float64
+		want  Color
+	}{
+		{Depth16, 0, ColorGreen},
+		{Depth16, .5, ColorYellow},
+		{Depth16, 1, ColorRed},
+		{Depth256, 0, Color256(46)},
+		{Depth256, .5, Color256(226)},
+		{Depth256, 1, Color256(196)},
+		{DepthTrueColor, 0, RGB(0, 255, 0)},
+		{DepthTrueColor, .25, RGB(128, 255, 0)},
+		{DepthTrueColor, .5, RGB(255, 255, 0)},
+		{DepthTrueColor, 1, RGB(255, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			if have := tt.depth.gradient(tt.pos); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have.sgr(false), tt.want.sgr(false))
+			}
+		})
+	}
+}
+
+func TestHeatmapCol(t *testing.T) {
+	tbl := New("Name", "Time", "Size").
+		Rows(
+			"a", 10*time.Millisecond, uint8(1),
+			"b", 20*time.Millisecond, uint8(200),
+			"c", 30*time.Millisecond, "?").
+		HeatmapCol(1, &Heatmap{Depth: Depth16}).
+		HeatmapCol(2, &Heatmap{Min: 100, Max: 150, Reverse: true, Depth: DepthTrueColor})
+
+	test(t, tbl.Horizontal, `
+		  Name  │  Time  │  Size
+		────────┼────────┼────────
+		  a     │  `+"\x1b[32m10ms\x1b[0m"+`  │  `+"\x1b[38;2;255;0;0m   1\x1b[0m"+`
+		  b     │  `+"\x1b[33m20ms\x1b[0m"+`  │  `+"\x1b[38;2;0;255;0m 200\x1b[0m"+`
+		  c     │  `+"\x1b[31m30ms\x1b[0m"+`  │     ?
+	`)
+
+	tbl.HeatmapCol(1, nil)
+	test(t, tbl.Horizontal, `
+		  Name  │  Time  │  Size
+		────────┼────────┼────────
+		  a     │  10ms  │  `+"\x1b[38;2;255;0;0m   1\x1b[0m"+`
+		  b     │  20ms  │  `+"\x1b[38;2;0;255;0m 200\x1b[0m"+`
+		  c     │  30ms  │     ?
+	`)
+}
+
+func TestHeatValue(t *testing.T) {
+	type myInt int
+	tests := []struct {
+		in   any
+		want float64
+		ok   bool
+	}{
+		{5, 5, true},
+		{uint8(5), 5, true},
+		{float32(1.5), 1.5, true},
+		{time.Second, 1e9, true},
+		{complex(3, 4), 5, true},
+		{complex64(complex(-3, 4)), 5, true},
+		{myInt(5), 0, false},
+		{"5", 0, false},
+		{nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			have, ok := heatValue(tt.in)
+			if have != tt.want || ok != tt.ok {
+				t.Errorf("\nhave: %v, %t\nwant: %v, %t", have, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package acidtab
 
 import (
 	"io"
+	"strings"
 
 	"zgo.at/termtext"
 )
@@ -29,6 +30,25 @@ func getWriter(w io.Writer) writer {
 	return wrapWriter{w}
 }
 
+// stripWriter removes all SGR escape sequences.
+type stripWriter struct {
+	writer
+}
+
+func (w stripWriter) Write(b []byte) (int, error) {
+	return w.writer.Write([]byte(stripSGR(string(b))))
+}
+func (w stripWriter) WriteString(s string) (int, error) { return w.writer.WriteString(stripSGR(s)) }
+
+// termWriter gets the writer for Horizontal() and Vertical(), which removes
+// colours if they're disabled.
+func (t Table) termWriter(w io.Writer) writer {
+	if !t.useColor(w) {
+		return stripWriter{getWriter(w)}
+	}
+	return getWriter(w)
+}
+
 func fillRunes(r rune, n int) string {
 	d := make([]rune, n)
 	for i := range d {
@@ -45,86 +65,383 @@ func fillBytes(r byte, n int) string {
 }
 
 func (t Table) Horizontal(w io.Writer) {
-	b := getWriter(w)
+	b := t.termWriter(w)
+	t.rows, t.widths = t.barRows()
+	var (
+		footer         = t.footerRows()
+		rows, sep, idx = t.bodyRows(t.sepRows)
+		extra          = footer
+	)
+	if t.grouped {
+		extra = append(rows[:len(rows):len(rows)], footer...)
+	}
+	t.widths = t.fitWidths(extra...)
+	t.heatRange = t.heatRanges()
 	padStr := fillRunes(t.borders.Line, termtext.Width(t.pad))
 
-	if t.close&CloseTop != 0 {
-		t.horiLine(b, padStr,
-			t.borders.LineTop, t.borders.TopLeft, t.borders.TopRight)
+	/// Title and caption are inside the borders if the table is closed, and
+	/// aligned to the full width of the table otherwise.
+	var (
+		boxed      = t.close&CloseAll == CloseAll
+		titleWidth = len(t.widths) - 1
+	)
+	for _, w := range t.widths {
+		titleWidth += w + termtext.Width(t.pad)*2
 	}
-	if t.pHeader {
-		t.horiRow(b, t.header, true)
-		t.horiLine(b, padStr,
-			t.borders.Cross, t.borders.BarRight, t.borders.BarLeft)
+	if boxed {
+		titleWidth -= termtext.Width(t.pad) * 2
+	} else {
+		if t.close&CloseLeft != 0 {
+			titleWidth++
+		}
+		/// The padding after the last column is only printed with CloseRight.
+		if t.close&CloseRight != 0 {
+			titleWidth++
+		} else {
+			titleWidth -= termtext.Width(t.pad)
+		}
+	}
+	if titleWidth < 0 {
+		titleWidth = 0
 	}
 
-	for _, r := range t.rows {
+	/// Collect all rows first, so we know which borders the lines between the
+	/// rows need to connect to.
+	var items []horiItem
+	if t.pHeader {
+		for _, s := range t.headerSpans {
+			items = append(items, t.spanItem(s, true, len(items) > 0))
+		}
+		items = append(items, horiItem{cells: t.header, styles: t.styleHeader, center: true, line: len(items) > 0})
+	}
+	var stripe int
+	for i, r := range rows {
+		if !t.grouped {
+			for _, s := range t.spanRowsAt(i) {
+				items = append(items, t.spanItem(s, false, len(items) > 0))
+			}
+		}
 		if len(r) < len(t.header) {
 			m := make([]string, len(t.header))
 			copy(m, r)
 			r = m
 		}
-		t.horiRow(b, r, false)
+		styles := t.cellStyles(-1, -1)
+		if idx[i] >= 0 {
+			styles = t.cellStyles(idx[i], stripe)
+			stripe++
+		}
+		items = append(items, horiItem{cells: r, styles: styles,
+			line: sep[i] || (len(items) > 0 && (i == 0 || items[len(items)-1].cols != nil))})
+	}
+	if !t.grouped {
+		for _, s := range t.spanRowsAt(len(rows)) {
+			items = append(items, t.spanItem(s, false, len(items) > 0))
+		}
+	}
+	for i, r := range footer {
+		items = append(items, horiItem{cells: r, styles: t.cellStyles(-1, -1), line: i == 0 && len(items) > 0})
+	}
+
+	var first, last colBorders
+	if len(items) > 0 {
+		first, last = items[0].borders, items[len(items)-1].borders
+	}
+
+	switch {
+	case t.title != "" && boxed:
+		t.horiLine(b, padStr, noBorders, noBorders, t.borders.TopLeft, t.borders.TopRight)
+		t.titleLines(b, t.title, t.titleAlign, titleWidth, boxed, boxed)
+		t.horiLine(b, padStr, noBorders, first, t.borders.BarRight, t.borders.BarLeft)
+	case t.title != "":
+		t.titleLines(b, t.title, t.titleAlign, titleWidth, boxed, boxed)
+		fallthrough
+	default:
+		if t.close&CloseTop != 0 {
+			t.horiLine(b, padStr, noBorders, first, t.borders.TopLeft, t.borders.TopRight)
+		}
 	}
 
+	for i, it := range items {
+		if it.line {
+			t.horiLine(b, padStr, items[i-1].borders, it.borders, t.borders.BarRight, t.borders.BarLeft)
+		}
+		if it.cols != nil {
+			t.spanRow(b, it.cells, it.cols, it.center)
+		} else {
+			t.horiRow(b, it.cells, it.styles, it.center)
+		}
+	}
+	/// Always print the line below the header, even if there are no rows.
+	if t.pHeader && len(items) == len(t.headerSpans)+1 {
+		t.horiLine(b, padStr, nil, nil, t.borders.BarRight, t.borders.BarLeft)
+		last = nil
+	}
+
+	if t.caption != "" && boxed {
+		t.horiLine(b, padStr, last, noBorders, t.borders.BarRight, t.borders.BarLeft)
+		t.titleLines(b, t.caption, t.captionAlign, titleWidth, boxed, boxed)
+		t.horiLine(b, padStr, noBorders, noBorders, t.borders.BottomLeft, t.borders.BottomRight)
+		return
+	}
 	if t.close&CloseBottom != 0 {
-		t.horiLine(b, padStr,
-			t.borders.LineBottom, t.borders.BottomLeft, t.borders.BottomRight)
+		t.horiLine(b, padStr, last, noBorders, t.borders.BottomLeft, t.borders.BottomRight)
+	}
+	if t.caption != "" {
+		t.titleLines(b, t.caption, t.captionAlign, titleWidth, boxed, boxed)
 	}
 }
 
-func (t Table) horiRow(b writer, row []string, alwaysCenter bool) {
-	b.WriteString(t.prefix)
-	if t.close&CloseLeft != 0 {
-		b.WriteRune(t.borders.Bar)
+// horiItem is a row to print in Horizontal().
+type horiItem struct {
+	cells   []string
+	styles  []Style
+	cols    []int      // Number of columns for every cell in span rows.
+	center  bool       // Header row: always center cells and use the header style.
+	line    bool       // Print a line before this row.
+	borders colBorders // Borders after every column.
+}
+
+// rowLines gets which rows should have a line before them: rows after a
+// Separator(), or all rows except the first if all is set.
+func (t Table) rowLines(all bool) []bool {
+	lines := make([]bool, len(t.rows))
+	for i := 1; i < len(lines); i++ {
+		lines[i] = all
+	}
+	for _, s := range t.seps {
+		if s < len(lines) {
+			lines[s] = true
+		}
 	}
+	return lines
+}
+
+// titleLines writes a title or caption aligned in w columns, wrapping it if it's
+// wider. It's prefixed or suffixed with a bar if left or right are set.
+func (t Table) titleLines(b writer, s string, a Align, w int, left, right bool) {
+	if a == Auto {
+		a = Center
+	}
+	for _, line := range splitLines(s) {
+		lines := []string{line}
+		if termtext.Width(line) > w {
+			lines = wrapText(line, w)
+		}
+		for _, l := range lines {
+			b.WriteString(t.prefix)
+			if left {
+				b.WriteString(t.bar())
+				b.WriteString(t.pad)
+			}
+			writeAligned(b, l, w, a, right)
+			if right {
+				b.WriteString(t.pad)
+				b.WriteString(t.bar())
+			}
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// writeAligned writes s aligned in w columns; Auto is the same as Left. Spaces
+// after the text are only written if trail is set.
+func writeAligned(b writer, s string, w int, a Align, trail bool) {
+	fill := w - termtext.Width(s)
+	if fill < 0 {
+		fill = 0
+	}
+	switch a {
+	case Auto, Left:
+		b.WriteString(s)
+		if trail {
+			b.WriteString(fillBytes(' ', fill))
+		}
+	case Right:
+		b.WriteString(fillBytes(' ', fill))
+		b.WriteString(s)
+	case Center:
+		b.WriteString(fillBytes(' ', fill/2))
+		b.WriteString(s)
+		if trail {
+			b.WriteString(fillBytes(' ', fill-fill/2))
+		}
+	}
+}
+
+func (t Table) horiRow(b writer, row []string, styles []Style, alwaysCenter bool) {
+	var (
+		lines  = make([][]string, len(row))
+		height = 1
+	)
 	for i := range row {
-		/// In case the header was set to something larger later on.
 		if i > len(t.header)-1 {
-			continue
+			break
 		}
+		w := t.widths[i]
+		if !alwaysCenter {
+			w = t.maxCellWidth(i, w)
+		}
+		lines[i] = t.cellLines(i, row[i], w)
+		if len(lines[i]) > height {
+			height = len(lines[i])
+		}
+	}
 
-		b.WriteString(t.pad)
-		align := fillBytes(' ', t.widths[i]-termtext.Width(row[i]))
-		a := t.align[i]
-		if alwaysCenter {
-			a = Center
+	for l := 0; l < height; l++ {
+		b.WriteString(t.prefix)
+		if t.close&CloseLeft != 0 {
+			b.WriteString(t.bar())
 		}
-		switch a {
-		case Auto:
-			// TODO: this is set in a different location, and not correct after
+		for i := range row {
+			/// In case the header was set to something larger later on.
+			if i > len(t.header)-1 {
+				continue
+			}
+
+			var cell string
+			if l < len(lines[i]) {
+				cell = lines[i][l]
+			}
+
+			b.WriteString(t.pad)
+			// TODO: Auto is set in a different location, and not correct after
 			// increasing the header size.
-			fallthrough
-		case Left:
-			b.WriteString(row[i])
-			if t.close&CloseRight != 0 || i != len(row)-1 {
-				b.WriteString(align)
+			a := t.align[i]
+			if alwaysCenter {
+				a = Center
+			}
+			if a == Decimal {
+				cell, a = t.decimalCell(i, cell), Left
 			}
-		case Right:
-			b.WriteString(align)
-			b.WriteString(row[i])
-		case Center:
-			l := len(align)
-			align = align[:l/2]
-			b.WriteString(align)
-			b.WriteString(row[i])
-			if t.close&CloseRight != 0 || i != len(row)-1 {
-				if l%2 == 1 {
-					b.WriteByte(' ')
-				}
-				b.WriteString(align)
+			var (
+				trail = t.close&CloseRight != 0 || i != len(row)-1
+				st    Style
+			)
+			if i < len(styles) {
+				st = styles[i]
+			}
+			if alwaysCenter {
+				st = t.headerStyle.merge(st)
+			}
+			if st != (Style{}) {
+				sb := new(strings.Builder)
+				writeAligned(sb, cell, t.widths[i], a, trail)
+				b.WriteString(st.apply(sb.String()))
+			} else {
+				writeAligned(b, cell, t.widths[i], a, trail)
+			}
+			if trail {
+				b.WriteString(t.pad)
+				b.WriteString(t.bar())
 			}
 		}
-		if t.close&CloseRight != 0 || i != len(row)-1 {
-			b.WriteString(t.pad)
-			b.WriteRune(t.borders.Bar)
+		b.WriteByte('\n')
+	}
+}
+
+// decimalCell pads s so that the decimal separator lines up with the other
+// cells in column col, and the cell with the widest fraction is right-aligned.
+func (t Table) decimalCell(col int, s string) string {
+	n, _ := t.decimalWidths(s)
+	pad := t.widths[col] - t.fracWidth[col] - n
+	if m := t.widths[col] - termtext.Width(s); pad > m {
+		pad = m
+	}
+	if pad <= 0 {
+		return s
+	}
+	return fillBytes(' ', pad) + s
+}
+
+// cellLines gets the lines to print for a cell, truncating or wrapping any
+// lines that are wider than w.
+func (t Table) cellLines(col int, s string, w int) []string {
+	lines := splitLines(s)
+	if len(lines) == 1 && termtext.Width(s) <= w {
+		return lines
+	}
+
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		switch {
+		case termtext.Width(l) <= w:
+			out = append(out, l)
+		case t.wrap[col] > 0 || t.overflow == OverflowWrap:
+			out = append(out, wrapText(l, w)...)
+		default:
+			out = append(out, truncate(l, w, t.ellipsis, t.truncAt[col]))
 		}
 	}
-	b.WriteByte('\n')
+	return out
 }
 
-func (t Table) horiLine(b writer, padStr string, cross, first, last rune) {
+// fitWidths gets the column widths so that the table fits in maxWidth. The
+// widths are increased if the cells in extra are wider.
+//
+// This always returns a new slice.
+func (t Table) fitWidths(extra ...[]string) []int {
+	widths := make([]int, len(t.widths))
+	copy(widths, t.widths)
+	for _, r := range extra {
+		for i := range r {
+			if l := t.cellWidth(i, r[i]); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+	t.spanWidths(widths)
+	if t.maxWidth == 0 {
+		return widths
+	}
+
+	/// Width of everything except the cells.
+	var (
+		padWidth = termtext.Width(t.pad)
+		over     = termtext.Width(t.prefix) + len(widths)*padWidth*2 + len(widths) - 1 - t.maxWidth
+	)
+	if t.close&CloseLeft != 0 {
+		over++
+	}
+	if t.close&CloseRight != 0 {
+		over++
+	}
+	for _, w := range widths {
+		over += w
+	}
+
+	for ; over > 0; over-- {
+		shrink := -1
+		for i := range widths {
+			if widths[i] <= t.minWidthCol(i) {
+				continue
+			}
+			if shrink == -1 || t.priority[i] < t.priority[shrink] ||
+				(t.priority[i] == t.priority[shrink] && widths[i] > widths[shrink]) {
+				shrink = i
+			}
+		}
+		if shrink == -1 {
+			break
+		}
+		widths[shrink]--
+	}
+	return widths
+}
+
+func (t Table) minWidthCol(n int) int {
+	if t.minWidth[n] > 0 {
+		return t.minWidth[n]
+	}
+	return 3
+}
+
+// horiLine writes a horizontal line between two rows with the column borders
+// above and below it, using the correct junction characters.
+func (t Table) horiLine(b writer, padStr string, above, below colBorders, first, last rune) {
 	b.WriteString(t.prefix)
+	sgr := t.borderStyle.sgr()
+	b.WriteString(sgr)
 	if t.close&CloseLeft != 0 {
 		b.WriteRune(first)
 	}
@@ -133,10 +450,25 @@ func (t Table) horiLine(b writer, padStr string, cross, first, last rune) {
 		b.WriteString(fillRunes(t.borders.Line, t.widths[i]))
 		b.WriteString(padStr)
 		if i < len(t.header)-1 {
-			b.WriteRune(cross)
+			switch up, down := above.has(i), below.has(i); {
+			case up && down:
+				b.WriteRune(t.borders.Cross)
+			case up:
+				b.WriteRune(t.borders.LineBottom)
+			case down:
+				b.WriteRune(t.borders.LineTop)
+			default:
+				b.WriteRune(t.borders.Line)
+			}
 		} else if t.close&CloseRight != 0 {
 			b.WriteRune(last)
 		}
 	}
+	if sgr != "" {
+		b.WriteString("\x1b[0m")
+	}
 	b.WriteByte('\n')
 }
+
+// bar gets the bar character, with the StyleBorders() style.
+func (t Table) bar() string { return t.borderStyle.apply(string(t.borders.Bar)) }
@@ -0,0 +1,228 @@
+package acidtab
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HTML prints the table as a HTML <table>.
+//
+// The column alignment and any styles set with StyleCol() and such are set as a
+// style attribute on the cells, and all cell content is HTML-escaped. Terminal
+// escape sequences are removed, unless EscapesToHTML() is set in which case
+// colours and text attributes are converted to <span> elements.
+//
+// The borders, padding, prefix, and close options are not used.
+func (t Table) HTML(w io.Writer) {
+	b := getWriter(w)
+	t.rows, t.widths = t.barRows()
+	t.heatRange = t.heatRanges()
+
+	b.WriteString("<table>\n")
+	if t.pHeader {
+		b.WriteString("<thead>\n<tr>")
+		for i, h := range t.exportRow(t.header) {
+			b.WriteString(htmlTag("th", t.htmlCSS(i, t.styleHeader[i])))
+			b.WriteString(t.htmlCell(h))
+			b.WriteString("</th>")
+		}
+		b.WriteString("</tr>\n</thead>\n")
+	}
+
+	b.WriteString("<tbody>\n")
+	for n, r := range t.rows {
+		b.WriteString("<tr>")
+		styles := t.cellStyles(n, n)
+		for i, c := range t.exportRow(r) {
+			b.WriteString(htmlTag("td", t.htmlCSS(i, styles[i])))
+			b.WriteString(t.htmlCell(c))
+			b.WriteString("</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+}
+
+// htmlCSS gets the CSS for a cell in column col: the column alignment and the
+// style.
+func (t Table) htmlCSS(col int, st Style) string {
+	css := make([]string, 0, 2)
+	switch t.align[col] {
+	case Left:
+		css = append(css, "text-align: left")
+	case Right, Decimal:
+		css = append(css, "text-align: right")
+	case Center:
+		css = append(css, "text-align: center")
+	}
+	if s := st.css(); s != "" {
+		css = append(css, s)
+	}
+	return strings.Join(css, "; ")
+}
+
+// htmlTag gets the opening tag for an element with an optional style
+// attribute.
+func htmlTag(tag, css string) string {
+	if css == "" {
+		return "<" + tag + ">"
+	}
+	return "<" + tag + ` style="` + css + `">`
+}
+
+func (t Table) htmlCell(s string) string {
+	if t.escToHTML {
+		s = sgrToHTML(s)
+	} else {
+		s = html.EscapeString(stripEscapes(s))
+	}
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// sgrToHTML converts SGR escape sequences in s to <span> elements with a style
+// attribute. Any other escape sequences are removed.
+func sgrToHTML(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return html.EscapeString(s)
+	}
+
+	var (
+		b     = new(strings.Builder)
+		state Style
+		open  bool
+	)
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\x1b')
+		if i == -1 {
+			i = len(s)
+		}
+		if i > 0 {
+			if !open && state != (Style{}) {
+				b.WriteString(`<span style="`)
+				b.WriteString(state.css())
+				b.WriteString(`">`)
+				open = true
+			}
+			b.WriteString(html.EscapeString(s[:i]))
+			s = s[i:]
+			continue
+		}
+
+		l := escapeLen(s)
+		if seq := s[:l]; l > 2 && seq[1] == '[' && seq[l-1] == 'm' {
+			if open {
+				b.WriteString("</span>")
+				open = false
+			}
+			state.applySGR(seq[2 : l-1])
+		}
+		s = s[l:]
+	}
+	if open {
+		b.WriteString("</span>")
+	}
+	return b.String()
+}
+
+// applySGR applies the SGR parameters in params (e.g. "1;31") to the style.
+func (s *Style) applySGR(params string) {
+	p := strings.Split(params, ";")
+	for i := 0; i < len(p); i++ {
+		n, _ := strconv.Atoi(p[i]) // Empty is the same as 0.
+		switch {
+		case n == 0:
+			*s = Style{}
+		case n == 1:
+			s.Bold = true
+		case n == 2:
+			s.Dim = true
+		case n == 3:
+			s.Italic = true
+		case n == 4:
+			s.Underline = true
+		case n == 22:
+			s.Bold, s.Dim = false, false
+		case n == 23:
+			s.Italic = false
+		case n == 24:
+			s.Underline = false
+		case n >= 30 && n <= 37:
+			s.Fg = ColorBlack + Color(n-30)
+		case n >= 90 && n <= 97:
+			s.Fg = ColorBrightBlack + Color(n-90)
+		case n == 39:
+			s.Fg = 0
+		case n >= 40 && n <= 47:
+			s.Bg = ColorBlack + Color(n-40)
+		case n >= 100 && n <= 107:
+			s.Bg = ColorBrightBlack + Color(n-100)
+		case n == 49:
+			s.Bg = 0
+		case n == 38 || n == 48:
+			var c Color
+			c, i = extendedColor(p, i)
+			if n == 38 {
+				s.Fg = c
+			} else {
+				s.Bg = c
+			}
+		}
+	}
+}
+
+// extendedColor parses the 256-colour ("38;5;n") or truecolor ("38;2;r;g;b")
+// parameters starting at p[i], returning the colour and the index of the last
+// parameter that was used.
+func extendedColor(p []string, i int) (Color, int) {
+	if i+1 >= len(p) {
+		return 0, i
+	}
+	switch p[i+1] {
+	case "5":
+		if i+2 >= len(p) {
+			return 0, len(p)
+		}
+		n, _ := strconv.Atoi(p[i+2])
+		return Color256(uint8(n)), i + 2
+	case "2":
+		if i+4 >= len(p) {
+			return 0, len(p)
+		}
+		r, _ := strconv.Atoi(p[i+2])
+		g, _ := strconv.Atoi(p[i+3])
+		b, _ := strconv.Atoi(p[i+4])
+		return RGB(uint8(r), uint8(g), uint8(b)), i + 4
+	}
+	return 0, i + 1
+}
+
+// The 16 standard colours, as rendered by xterm.
+var xterm16 = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// xtermColor gets the CSS colour for the 256-colour palette entry n.
+func xtermColor(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return xterm16[n]
+	case n < 232:
+		n -= 16
+		level := func(c int) int {
+			if c == 0 {
+				return 0
+			}
+			return 55 + c*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		g := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", g, g, g)
+	}
+}
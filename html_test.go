@@ -0,0 +1,58 @@
+package acidtab
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tbl := New("\x1b[1mName\x1b[0m", "Count", "Status").
+		AlignCol(2, Center).
+		Rows(
+			"<b> & co", 1, "\x1b[32mok\x1b[0m",
+			"two\nlines", 1234, "\x1b[1;38;5;196mfailed\x1b[22m!\x1b[0m",
+		)
+
+	test(t, tbl.HTML, `
+		<table>
+		<thead>
+		<tr><th style="text-align: left">Name</th><th style="text-align: right">Count</th><th style="text-align: center">Status</th></tr>
+		</thead>
+		<tbody>
+		<tr><td style="text-align: left">&lt;b&gt; &amp; co</td><td style="text-align: right">1</td><td style="text-align: center">ok</td></tr>
+		<tr><td style="text-align: left">two<br>lines</td><td style="text-align: right">1234</td><td style="text-align: center">failed!</td></tr>
+		</tbody>
+		</table>
+	`)
+
+	tbl.EscapesToHTML(true).Header(false)
+	test(t, tbl.HTML, `
+		<table>
+		<tbody>
+		<tr><td style="text-align: left">&lt;b&gt; &amp; co</td><td style="text-align: right">1</td><td style="text-align: center"><span style="color: #00cd00">ok</span></td></tr>
+		<tr><td style="text-align: left">two<br>lines</td><td style="text-align: right">1234</td><td style="text-align: center"><span style="font-weight: bold; color: #ff0000">failed</span><span style="color: #ff0000">!</span></td></tr>
+		</tbody>
+		</table>
+	`)
+}
+
+func TestXtermColor(t *testing.T) {
+	tests := []struct {
+		in   int
+		want string
+	}{
+		{-1, ""},
+		{1, "#cd0000"},
+		{16, "#000000"},
+		{196, "#ff0000"},
+		{231, "#ffffff"},
+		{232, "#080808"},
+		{255, "#eeeeee"},
+		{256, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			if have := xtermColor(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package acidtab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSON writes the table as an array of JSON objects, with the header as keys.
+//
+// The values are the original values as passed to Row() and Rows(), rather
+// than the formatted strings; for example numbers are written as JSON numbers
+// and booleans as JSON booleans. Values that can't be encoded as JSON (such as
+// complex numbers) are written as the formatted string. Missing cells are
+// written as null.
+//
+// Terminal escape sequences are always removed from the keys. An error is
+// returned if two columns have the same key (after removing the escapes), as
+// the objects would have duplicate keys; use JSONArray() for these tables.
+func (t Table) JSON(w io.Writer) error {
+	keys := make([][]byte, len(t.header))
+	seen := make(map[string]int, len(t.header))
+	for i := range t.header {
+		k := stripEscapes(t.header[i])
+		if j, ok := seen[k]; ok {
+			return fmt.Errorf("JSON: columns %d and %d have the same key %q", j, i, k)
+		}
+		seen[k] = i
+		keys[i] = jsonValue(k, "")
+	}
+
+	b := new(bytes.Buffer)
+	b.WriteByte('[')
+	for i := range t.raw {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n  {")
+		cells := t.exportRow(t.rows[i])
+		for j := range keys {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.Write(keys[j])
+			b.WriteString(": ")
+			b.Write(t.jsonCell(i, j, cells))
+		}
+		b.WriteByte('}')
+	}
+	if len(t.raw) > 0 {
+		b.WriteByte('\n')
+	}
+	b.WriteString("]\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// JSONArray writes the table as an array of arrays.
+//
+// The first array is the header if it's enabled with Header(), with any
+// terminal escape sequences removed. The values are written in the same way as
+// in JSON().
+func (t Table) JSONArray(w io.Writer) error {
+	b := new(bytes.Buffer)
+	b.WriteByte('[')
+	if t.pHeader {
+		b.WriteString("\n  [")
+		for i := range t.header {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.Write(jsonValue(stripEscapes(t.header[i]), ""))
+		}
+		b.WriteByte(']')
+	}
+	for i := range t.raw {
+		if i > 0 || t.pHeader {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n  [")
+		cells := t.exportRow(t.rows[i])
+		for j := range t.header {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.Write(t.jsonCell(i, j, cells))
+		}
+		b.WriteByte(']')
+	}
+	if len(t.raw) > 0 || t.pHeader {
+		b.WriteByte('\n')
+	}
+	b.WriteString("]\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// jsonCell gets the JSON for a cell, using the formatted cells as fallback.
+func (t Table) jsonCell(row, col int, cells []string) []byte {
+	if col > len(t.raw[row])-1 {
+		return []byte("null")
+	}
+	return jsonValue(t.raw[row][col], cells[col])
+}
+
+func jsonValue(v any, fallback string) []byte {
+	b := new(bytes.Buffer)
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		b.Reset()
+		e.Encode(fallback)
+	}
+	return bytes.TrimRight(b.Bytes(), "\n")
+}
@@ -0,0 +1,51 @@
+package acidtab
+
+import (
+	"io"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tbl := New("\x1b[1mName\x1b[0m", "Count", "Alive", "Complex").
+		FormatColFunc(1, FormatAsNum()).
+		Rows(
+			"<a>", 1234, true, complex(1, 2),
+			`"q"`, 1.5, false, nil,
+		).
+		Row("short")
+
+	test(t, noErr(t, tbl.JSON), `
+		[
+		  {"Name": "<a>", "Count": 1234, "Alive": true, "Complex": "(1+2i)"},
+		  {"Name": "\"q\"", "Count": 1.5, "Alive": false, "Complex": null},
+		  {"Name": "short", "Count": null, "Alive": null, "Complex": null}
+		]
+	`)
+	test(t, noErr(t, tbl.JSONArray), `
+		[
+		  ["Name", "Count", "Alive", "Complex"],
+		  ["<a>", 1234, true, "(1+2i)"],
+		  ["\"q\"", 1.5, false, null],
+		  ["short", null, null, null]
+		]
+	`)
+
+	tbl.Header(false)
+	test(t, noErr(t, tbl.JSONArray), `
+		[
+		  ["<a>", 1234, true, "(1+2i)"],
+		  ["\"q\"", 1.5, false, null],
+		  ["short", null, null, null]
+		]
+	`)
+
+	test(t, noErr(t, New("a").JSON), `[]`)
+	test(t, noErr(t, New("a").Header(false).JSONArray), `[]`)
+
+	for _, h := range [][]string{{"a", "a"}, {"", "x", ""}, {"\x1b[1ma\x1b[0m", "a"}} {
+		err := New(h...).Row("1").JSON(io.Discard)
+		if !errorContains(err, "have the same key") {
+			t.Errorf("wrong error for %q: %v", h, err)
+		}
+	}
+}
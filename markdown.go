@@ -0,0 +1,112 @@
+package acidtab
+
+import (
+	"io"
+	"strings"
+
+	"zgo.at/termtext"
+)
+
+// Markdown prints the table as a GitHub-flavoured Markdown "pipe table".
+//
+// The column alignment is set with the :--, --:, and :-: markers; the raw
+// Markdown text is aligned as well. Any | characters are escaped and newlines
+// are replaced with <br>. Use StripEscapes() to remove terminal escape
+// sequences, which aren't rendered by Markdown.
+//
+// The borders, padding, prefix, and close options are not used. Markdown
+// tables always need a header row, so Header(false) prints an empty header.
+func (t Table) Markdown(w io.Writer) {
+	b := getWriter(w)
+
+	header := make([]string, len(t.header))
+	if t.pHeader {
+		header = t.exportRow(t.header)
+		for i := range header {
+			header[i] = mdEscape(header[i])
+		}
+	}
+	rows := make([][]string, 0, len(t.rows))
+	for _, r := range t.rows {
+		row := t.exportRow(r)
+		for i := range row {
+			row[i] = mdEscape(row[i])
+		}
+		rows = append(rows, row)
+	}
+
+	// Escaping may make the text a bit wider than what we calculated when
+	// adding the rows; the delimiter row also needs at least 3 characters.
+	widths := make([]int, len(t.header))
+	copy(widths, t.widths)
+	for i := range widths {
+		if widths[i] < 3 {
+			widths[i] = 3
+		}
+		if l := termtext.Width(header[i]); l > widths[i] {
+			widths[i] = l
+		}
+		for _, r := range rows {
+			if l := termtext.Width(r[i]); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+
+	t.mdRow(b, widths, header)
+	b.WriteByte('|')
+	for i := range t.header {
+		b.WriteByte(' ')
+		switch t.align[i] {
+		case Left:
+			b.WriteByte(':')
+			b.WriteString(fillBytes('-', widths[i]-1))
+		case Right, Decimal:
+			b.WriteString(fillBytes('-', widths[i]-1))
+			b.WriteByte(':')
+		case Center:
+			b.WriteByte(':')
+			b.WriteString(fillBytes('-', widths[i]-2))
+			b.WriteByte(':')
+		default:
+			b.WriteString(fillBytes('-', widths[i]))
+		}
+		b.WriteString(" |")
+	}
+	b.WriteByte('\n')
+	for _, r := range rows {
+		t.mdRow(b, widths, r)
+	}
+}
+
+func (t Table) mdRow(b writer, widths []int, row []string) {
+	b.WriteByte('|')
+	for i := range row {
+		b.WriteByte(' ')
+		align := fillBytes(' ', widths[i]-termtext.Width(row[i]))
+		switch t.align[i] {
+		case Right, Decimal:
+			b.WriteString(align)
+			b.WriteString(row[i])
+		case Center:
+			l := len(align)
+			b.WriteString(align[:l/2])
+			b.WriteString(row[i])
+			b.WriteString(align[l/2:])
+		default:
+			b.WriteString(row[i])
+			b.WriteString(align)
+		}
+		b.WriteString(" |")
+	}
+	b.WriteByte('\n')
+}
+
+var mdReplacer = strings.NewReplacer(
+	`|`, `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+	"\r", "<br>",
+)
+
+func mdEscape(s string) string { return mdReplacer.Replace(s) }
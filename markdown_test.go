@@ -0,0 +1,34 @@
+package acidtab
+
+import "testing"
+
+func TestMarkdown(t *testing.T) {
+	tbl := New("Name", "Count", "Status", "x").
+		AlignCol(2, Center).
+		Rows(
+			"a|b", 1, "ok", "",
+			"line\nbreak", 1234, "failed", "",
+		)
+
+	test(t, tbl.Markdown, `
+		| Name          | Count | Status | x   |
+		| :------------ | ----: | :----: | :-- |
+		| a\|b          |     1 |   ok   |     |
+		| line<br>break |  1234 | failed |     |
+	`)
+
+	tbl.Header(false)
+	test(t, tbl.Markdown, `
+		|               |       |        |     |
+		| :------------ | ----: | :----: | :-- |
+		| a\|b          |     1 |   ok   |     |
+		| line<br>break |  1234 | failed |     |
+	`)
+
+	tbl = New("a", "b").Row(1, 2).Header(true, "a")
+	test(t, tbl.Markdown, `
+		|   a |
+		| --: |
+		|   1 |
+	`)
+}
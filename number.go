@@ -0,0 +1,152 @@
+package acidtab
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	// NumberFormat sets how to print numbers. The Format method can be used
+	// with FormatColFunc():
+	//
+	//	t.FormatColFunc(1, acidtab.NumberDE.Format)
+	NumberFormat struct {
+		Group     string   // Group separator, such as "," in 1,000; "" to not group digits.
+		Decimal   string   // Decimal separator; "." if empty.
+		Grouping  Grouping // How to group the digits.
+		Precision int      // Number of decimals; use -1 to print as many as needed.
+		Plus      bool     // Add "+" before positive numbers.
+		Parens    bool     // Print negative numbers as (1,000) instead of -1,000.
+	}
+
+	Grouping uint8 // How to group digits.
+)
+
+// How to group digits.
+const (
+	GroupWestern Grouping = iota // Groups of three: 1,234,567.
+	GroupIndian                  // Lakh and crore: 12,34,567.
+)
+
+// Number formats for some common locales.
+var (
+	NumberEN = NumberFormat{Group: ","}                        // 1,234,567
+	NumberDE = NumberFormat{Group: ".", Decimal: ","}          // 1.234.567
+	NumberFR = NumberFormat{Group: "\u202f", Decimal: ","}     // 1 234 567 (narrow no-break space)
+	NumberCH = NumberFormat{Group: "’"}                        // 1’234’567
+	NumberIN = NumberFormat{Group: ",", Grouping: GroupIndian} // 12,34,567
+)
+
+// Format v as a number.
+//
+// This works for all integer and float types, and strings with a number. It
+// returns a NULL byte for everything else, so it will fall back to the regular
+// formatting with FormatColFunc().
+func (f NumberFormat) Format(v any) string {
+	var (
+		s   string
+		neg bool
+	)
+	switch vv := v.(type) {
+	case string:
+		return f.formatString(vv)
+	case []byte:
+		return f.formatString(string(vv))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	default:
+		return "\x00"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := uint64(rv.Int())
+		if rv.Int() < 0 {
+			neg, n = true, -n
+		}
+		s = strconv.FormatUint(n, 10)
+		if f.Precision > 0 {
+			s += "." + strings.Repeat("0", f.Precision)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(rv.Uint(), 10)
+		if f.Precision > 0 {
+			s += "." + strings.Repeat("0", f.Precision)
+		}
+	case reflect.Float32, reflect.Float64:
+		bits := 64
+		if rv.Kind() == reflect.Float32 {
+			bits = 32
+		}
+		s = strconv.FormatFloat(rv.Float(), 'f', f.Precision, bits)
+		if s == "NaN" || s == "+Inf" || s == "-Inf" {
+			return s
+		}
+		if s[0] == '-' {
+			s = s[1:]
+			/// Don't print "-0" for numbers that are rounded to 0.
+			neg = strings.Trim(s, "0.") != ""
+		}
+	}
+	return f.format(s, neg)
+}
+
+// formatString formats a number in a string, or returns s as-is if it's not a
+// number.
+func (f NumberFormat) formatString(s string) string {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return f.Format(n)
+	}
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return f.Format(n)
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return f.Format(n)
+	}
+	return s
+}
+
+// format the digits in s, which is a positive number with "." as the decimal
+// separator.
+func (f NumberFormat) format(s string, neg bool) string {
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	b := new(strings.Builder)
+	switch {
+	case neg && f.Parens:
+		b.WriteByte('(')
+	case neg:
+		b.WriteByte('-')
+	case f.Plus && strings.Trim(s, "0.") != "":
+		b.WriteByte('+')
+	}
+
+	for i := range intPart {
+		if i > 0 && f.Group != "" && f.groupAt(len(intPart)-i) {
+			b.WriteString(f.Group)
+		}
+		b.WriteByte(intPart[i])
+	}
+	if frac != "" {
+		if f.Decimal == "" {
+			b.WriteByte('.')
+		} else {
+			b.WriteString(f.Decimal)
+		}
+		b.WriteString(frac)
+	}
+
+	if neg && f.Parens {
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// groupAt reports if there should be a group separator before the digit that
+// has n digits after it (including itself).
+func (f NumberFormat) groupAt(n int) bool {
+	if f.Grouping == GroupIndian && n > 3 {
+		return (n-3)%2 == 0
+	}
+	return n%3 == 0
+}
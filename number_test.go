@@ -0,0 +1,72 @@
+package acidtab
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		f    NumberFormat
+		in   any
+		want string
+	}{
+		{NumberEN, 0, "0"},
+		{NumberEN, 999, "999"},
+		{NumberEN, 1234, "1,234"},
+		{NumberEN, int16(-9999), "-9,999"},
+		{NumberEN, int64(math.MinInt64), "-9,223,372,036,854,775,808"},
+		{NumberEN, uint64(123456789), "123,456,789"},
+		{NumberEN, 12341.123131, "12,341"},
+		{NumberEN, float32(1234.5), "1,234"},
+		{NumberEN, -0.4, "0"},
+		{NumberEN, math.NaN(), "NaN"},
+		{NumberEN, "1234567", "1,234,567"},
+		{NumberEN, []byte("-1234.5"), "-1,234"},
+		{NumberEN, "asd", "asd"},
+		{NumberEN, true, "\x00"},
+
+		{NumberFormat{Precision: 2}, 1234, "1234.00"},
+		{NumberFormat{Group: ",", Precision: -1}, 1234.125, "1,234.125"},
+		{NumberFormat{Group: ",", Precision: -1}, float32(0.1), "0.1"},
+		{NumberFormat{Group: ",", Precision: 1}, 1234.56, "1,234.6"},
+		{NumberFormat{Plus: true}, 5, "+5"},
+		{NumberFormat{Plus: true}, 0, "0"},
+		{NumberFormat{Plus: true}, -5, "-5"},
+		{NumberFormat{Group: ",", Parens: true}, -1234, "(1,234)"},
+		{NumberFormat{Group: ",", Parens: true}, 1234, "1,234"},
+
+		{NumberDE, 1234567, "1.234.567"},
+		{NumberFormat{Group: ".", Decimal: ",", Precision: 2}, -1234.5, "-1.234,50"},
+		{NumberFR, 1234567, "1 234 567"},
+		{NumberCH, 1234567, "1’234’567"},
+		{NumberIN, 123, "123"},
+		{NumberIN, 1234, "1,234"},
+		{NumberIN, 12345, "12,345"},
+		{NumberIN, 1234567, "12,34,567"},
+		{NumberIN, int64(123456789), "12,34,56,789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v", tt.want), func(t *testing.T) {
+			if have := tt.f.Format(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+
+	tbl := New("Name", "Balance").
+		FormatColFunc(1, NumberFormat{Group: ".", Decimal: ",", Precision: 2, Parens: true}.Format).
+		Rows(
+			"a", 1234.5,
+			"b", -99.999,
+			"c", 5)
+	test(t, tbl.Horizontal, `
+		  Name  │  Balance
+		────────┼────────────
+		  a     │  1.234,50
+		  b     │  (100,00)
+		  c     │      5,00
+	`)
+}
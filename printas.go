@@ -2,98 +2,309 @@ package acidtab
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // FormatAsFloat prints n as a float with the given percision.
 //
-// Use perc=0 to round to the nearest natural number.
+// Use perc=0 to round to the nearest natural number. Values that aren't a
+// float32 or float64 are printed with the regular formatting.
 func FormatAsFloat(perc int) FormatAsFunc {
 	return func(v any) string {
 		var f float64
 		switch vv := v.(type) {
 		default:
-			// TODO: maybe add error return to FormatAsFunc?
-			panic(fmt.Sprintf("acidtab.FormatAsFloat: not a float but %T: %[1]v", v))
-
+			return "\x00"
 		case float64:
 			f = vv
 		case float32:
 			f = float64(vv)
 		}
 
-		if f < 1 {
-			return fmt.Sprintf("%."+strconv.Itoa(perc)+"f", f)[1:]
-		}
-		return fmt.Sprintf("%0."+strconv.Itoa(perc)+"f", v)
+		return fmt.Sprintf("%0."+strconv.Itoa(perc)+"f", f)
 	}
 }
 
 // FormatAsNum prints n as a number with , as thousands separators.
+//
+// This is the same as NumberEN.Format; use a NumberFormat for other separators
+// and options.
 func FormatAsNum() FormatAsFunc {
+	return NumberEN.Format
+}
+
+// Bytes sets how FormatAsBytes() prints sizes.
+type Bytes uint8
+
+// How to print sizes.
+const (
+	BytesIEC   Bytes = 0      // Use 1024-based units: KiB, MiB, GiB, etc.
+	BytesSI    Bytes = 1 << 0 // Use 1000-based units: kB, MB, GB, etc.
+	BytesAlign Bytes = 1 << 1 // Pad the unit with spaces so all units have the same width.
+)
+
+var (
+	unitsIEC = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"}
+	unitsSI  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
+)
+
+// FormatAsBytes prints a number of bytes as a human-readable size, such as
+// "1.5 KiB".
+//
+// The base sets the units, and can be combined with BytesAlign to line up the
+// units in right-aligned columns:
+//
+//	t.FormatColFunc(1, acidtab.FormatAsBytes(acidtab.BytesSI|acidtab.BytesAlign, 1))
+//
+// The precision is the number of decimals, which are never printed for sizes
+// below 1 KiB (or kB). This works for all integer and float types, and strings
+// and []byte with a number, like FormatAsNum().
+func FormatAsBytes(base Bytes, precision int) FormatAsFunc {
+	units, div := unitsIEC, 1024.0
+	if base&BytesSI != 0 {
+		units, div = unitsSI, 1000
+	}
 	return func(v any) string {
-		// TODO: allow configuring this.
-		// There's also "indian style" where the grouping is different, but full
-		// locale parsing isn't really a goal here.
-		sep := ','
-		var s string
+		n, ok := parseFloat(v)
+		if !ok {
+			return "\x00"
+		}
 
-		switch vv := v.(type) {
+		var i int
+		for ; math.Abs(n) >= div && i < len(units)-1; i++ {
+			n /= div
+		}
+		p := precision
+		if i == 0 {
+			p = 0
+		}
+		s := strconv.FormatFloat(n, 'f', p, 64)
+		/// Rounding may give "1024.0 KiB", which should be "1.0 MiB".
+		if f, _ := strconv.ParseFloat(s, 64); math.Abs(f) >= div && i < len(units)-1 {
+			n, i = n/div, i+1
+			s = strconv.FormatFloat(n, 'f', precision, 64)
+		}
+
+		s += " " + units[i]
+		if base&BytesAlign != 0 {
+			s += strings.Repeat(" ", len(units[len(units)-1])-len(units[i]))
+		}
+		return s
+	}
+}
+
+var durUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"},
+	{time.Second, "s"}, {time.Millisecond, "ms"},
+}
+
+// FormatAsDuration prints a time.Duration with at most precision units, such as
+// "1m32s" or "2d4h" for a precision of 2. Use 0 to print all units.
+//
+// The duration is rounded to the smallest unit that's printed, and units that
+// are 0 are skipped. The smallest unit is milliseconds; shorter durations are
+// printed as "450µs" or "12ns".
+func FormatAsDuration(precision int) FormatAsFunc {
+	return func(v any) string {
+		d, ok := v.(time.Duration)
+		if !ok {
+			return "\x00"
+		}
+		return formatDuration(d, precision)
+	}
+}
+
+func formatDuration(d time.Duration, precision int) string {
+	var sign string
+	if d < 0 {
+		sign, d = "-", -d
+		/// -MinInt64 overflows; it's 1ns off, but that's always rounded away.
+		if d < 0 {
+			d = math.MaxInt64
+		}
+	}
+	switch {
+	case d == 0:
+		return "0s"
+	case d < time.Microsecond:
+		return sign + strconv.FormatInt(int64(d), 10) + "ns"
+	case d.Round(time.Microsecond) < time.Millisecond:
+		return sign + strconv.FormatInt(int64(d.Round(time.Microsecond)/time.Microsecond), 10) + "µs"
+	}
+
+	/// Rounding may make the duration larger than the next unit (59.6s →
+	/// 1m), so round again to the smallest unit for the new first unit.
+	var first, last int
+	for i := 0; i < 2; i++ {
+		for first = 0; first < len(durUnits)-1 && d < durUnits[first].d; first++ {
+		}
+		last = len(durUnits) - 1
+		if precision > 0 && first+precision-1 < last {
+			last = first + precision - 1
+		}
+		d = d.Round(durUnits[last].d)
+	}
+
+	b := new(strings.Builder)
+	b.WriteString(sign)
+	for _, u := range durUnits[first : last+1] {
+		if n := d / u.d; n > 0 {
+			b.WriteString(strconv.FormatInt(int64(n), 10))
+			b.WriteString(u.name)
+			d -= n * u.d
+		}
+	}
+	return b.String()
+}
+
+// FormatAsRelTime prints a time.Time relative to now in the largest unit that
+// fits, such as "3h ago" or "in 2d". Times less than a second from now are
+// printed as "now", and the zero time as an empty cell.
+//
+// Use the zero time for now to use the current time when the row is added.
+func FormatAsRelTime(now time.Time) FormatAsFunc {
+	return func(v any) string {
+		t, ok := v.(time.Time)
+		if !ok {
+			return "\x00"
+		}
+		if t.IsZero() {
+			return ""
+		}
+		n := now
+		if n.IsZero() {
+			n = time.Now()
+		}
+
+		d, f := n.Sub(t), "%d%s ago"
+		if d < 0 {
+			d, f = -d, "in %d%s"
+		}
+		switch {
+		case d < time.Second:
+			return "now"
+		case d < time.Minute:
+			return fmt.Sprintf(f, d/time.Second, "s")
+		case d < time.Hour:
+			return fmt.Sprintf(f, d/time.Minute, "m")
+		case d < 24*time.Hour:
+			return fmt.Sprintf(f, d/time.Hour, "h")
+		case d < 365*24*time.Hour:
+			return fmt.Sprintf(f, d/(24*time.Hour), "d")
 		default:
-			panic(fmt.Sprintf("acidtab.FormatAsNum: unsupported type: %T: %[1]v", vv))
-
-		// Not really numbers, but just allow it.
-		case string:
-			s = vv
-		case []byte:
-			s = string(vv)
-
-		case int:
-			s = strconv.FormatInt(int64(vv), 10)
-		case int8:
-			s = strconv.FormatInt(int64(vv), 10)
-		case int16:
-			s = strconv.FormatInt(int64(vv), 10)
-		case int32:
-			s = strconv.FormatInt(int64(vv), 10)
-		case int64:
-			s = strconv.FormatInt(vv, 10)
-		case uint:
-			s = strconv.FormatUint(uint64(vv), 10)
-		case uint8:
-			s = strconv.FormatUint(uint64(vv), 10)
-		case uint16:
-			s = strconv.FormatUint(uint64(vv), 10)
-		case uint32:
-			s = strconv.FormatUint(uint64(vv), 10)
-		case uint64:
-			s = strconv.FormatUint(vv, 10)
-		case float32:
-			s = strconv.FormatFloat(float64(vv), 'f', 0, 32)
-		case float64:
-			s = strconv.FormatFloat(vv, 'f', 0, 64)
+			return fmt.Sprintf(f, d/(365*24*time.Hour), "y")
+		}
+	}
+}
+
+// FormatAsTime prints a time.Time with the given layout, converted to loc. Use a
+// nil loc to not convert the time. The zero time is printed as an empty cell.
+func FormatAsTime(layout string, loc *time.Location) FormatAsFunc {
+	return func(v any) string {
+		t, ok := v.(time.Time)
+		if !ok {
+			return "\x00"
+		}
+		if t.IsZero() {
+			return ""
 		}
+		if loc != nil {
+			t = t.In(loc)
+		}
+		return t.Format(layout)
+	}
+}
 
-		if len(s) < 4 {
-			return s
+// FormatAsSparkline prints a []float64 as a sparkline, such as "▁▂▃▅▇█▅".
+//
+// The values are scaled from the lowest to the highest value. If there are more
+// values than width then they're averaged so the sparkline is at most width
+// columns wide; use 0 to not set a maximum. NaN values are printed as a space.
+func FormatAsSparkline(width int) FormatAsFunc {
+	return func(v any) string {
+		vals, ok := v.([]float64)
+		if !ok {
+			return "\x00"
 		}
+		return sparkline(vals, width)
+	}
+}
 
-		b := []byte(s)
-		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
-			b[i], b[j] = b[j], b[i]
+// FormatAsBar prints a number as a horizontal bar, such as "██████▍", which is
+// width columns wide for max.
+//
+// This works for all integer and float types; negative numbers and 0 are
+// printed as an empty cell. Also see BarCol() to scale the bars to the highest
+// value in the column.
+func FormatAsBar(max float64, width int) FormatAsFunc {
+	return func(v any) string {
+		f, ok := toFloat(v)
+		if !ok {
+			return "\x00"
 		}
+		return barText(f, max, width)
+	}
+}
+
+var (
+	sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+	barBlocks   = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+)
 
-		var out []rune
-		for i := range b {
-			if i > 0 && i%3 == 0 && sep > 1 {
-				out = append(out, sep)
+func sparkline(vals []float64, width int) string {
+	if width > 0 && len(vals) > width {
+		avg := make([]float64, width)
+		for i := range avg {
+			var (
+				sum float64
+				n   int
+			)
+			for _, f := range vals[i*len(vals)/width : (i+1)*len(vals)/width] {
+				if !math.IsNaN(f) {
+					sum, n = sum+f, n+1
+				}
+			}
+			avg[i] = math.NaN()
+			if n > 0 {
+				avg[i] = sum / float64(n)
 			}
-			out = append(out, rune(b[i]))
 		}
+		vals = avg
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, f := range vals {
+		if !math.IsNaN(f) {
+			lo, hi = math.Min(lo, f), math.Max(hi, f)
+		}
+	}
 
-		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
-			out[i], out[j] = out[j], out[i]
+	out := make([]rune, 0, len(vals))
+	for _, f := range vals {
+		switch {
+		case math.IsNaN(f):
+			out = append(out, ' ')
+		case hi > lo:
+			out = append(out, sparkBlocks[int(math.Round((f-lo)/(hi-lo)*float64(len(sparkBlocks)-1)))])
+		default:
+			out = append(out, sparkBlocks[0])
 		}
-		return string(out)
 	}
+	return string(out)
+}
+
+// barText gets a bar for f that is width columns wide for max, with a
+// precision of an eighth of a column.
+func barText(f, max float64, width int) string {
+	if !(f > 0) || max <= 0 || width <= 0 {
+		return ""
+	}
+	n := int(math.Round(math.Min(f/max, 1) * float64(width) * 8))
+	return strings.Repeat("█", n/8) + barBlocks[n%8]
 }
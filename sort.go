@@ -0,0 +1,334 @@
+package acidtab
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// SortKey is a column to sort by.
+	SortKey struct {
+		Col   int  // Column index.
+		Desc  bool // Sort descending, rather than ascending.
+		Order Order
+
+		// Custom comparison function, which should return a negative number if
+		// a < b, a positive number if a > b, and 0 if they're equal. Order is
+		// ignored if this is set.
+		Cmp func(a, b any) int
+	}
+
+	Order uint8 // How to compare values when sorting.
+)
+
+// How to compare values when sorting.
+const (
+	// Compare as numbers if both values are numbers, times if both values are a
+	// time.Time, and as strings otherwise.
+	SortAuto Order = iota
+
+	// Compare as numbers; strings are parsed as a number. Values that aren't
+	// numbers are sorted after numbers.
+	SortNumeric
+
+	// Compare as strings.
+	SortLexical
+
+	// Compare as strings, but compare sequences of digits as numbers, so that
+	// "file10" is sorted after "file9".
+	SortNatural
+)
+
+// SortBy sorts the rows by one or more columns.
+//
+// Rows are sorted by the first key, and rows that are equal for this key are
+// sorted by the next key, and so forth. The sort is stable.
+//
+// This uses the original values as passed to Row(), rather than the formatted
+// strings. Only the rows that were already added are sorted.
+//
+// Rows are only sorted within the groups added with Separator() and SpanRow();
+// rows are never moved to another group.
+func (t *Table) SortBy(keys ...SortKey) *Table {
+	for _, k := range keys {
+		if !t.checkN(k.Col, "SortBy") {
+			return t
+		}
+	}
+
+	idx := make([]int, len(t.raw))
+	for i := range idx {
+		idx[i] = i
+	}
+	bounds := append(make([]int, 0, len(t.seps)+len(t.spanRows)), t.seps...)
+	for _, s := range t.spanRows {
+		bounds = append(bounds, s.at)
+	}
+	sort.Ints(bounds)
+	var (
+		group = make([]int, len(t.raw)) // Group for every row.
+		g     int
+	)
+	for i := range group {
+		for g < len(bounds) && bounds[g] <= i {
+			g++
+		}
+		group[i] = g
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		if gi, gj := group[idx[i]], group[idx[j]]; gi != gj {
+			return gi < gj
+		}
+		a, b := t.raw[idx[i]], t.raw[idx[j]]
+		for _, k := range keys {
+			var va, vb any
+			if k.Col < len(a) {
+				va = a[k.Col]
+			}
+			if k.Col < len(b) {
+				vb = b[k.Col]
+			}
+
+			var c int
+			if k.Cmp != nil {
+				c = k.Cmp(va, vb)
+			} else {
+				c = compare(va, vb, k.Order)
+			}
+			if k.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	t.permute(idx)
+	return t
+}
+
+// permute reorders the rows so that row i is the row that was at idx[i].
+func (t *Table) permute(idx []int) {
+	var (
+		rows = make([][]string, len(t.rows), cap(t.rows))
+		raw  = make([][]any, len(t.raw), cap(t.raw))
+		pos  = make([]int, len(idx)) // New position of every row.
+	)
+	for i, j := range idx {
+		rows[i], raw[i] = t.rows[j], t.raw[j]
+		pos[j] = i
+	}
+	t.rows, t.raw = rows, raw
+
+	if len(t.styleRow) > 0 {
+		m := make(map[int]Style, len(t.styleRow))
+		for k, v := range t.styleRow {
+			m[pos[k]] = v
+		}
+		t.styleRow = m
+	}
+	if len(t.styleCell) > 0 {
+		m := make(map[[2]int]Style, len(t.styleCell))
+		for k, v := range t.styleCell {
+			m[[2]int{pos[k[0]], k[1]}] = v
+		}
+		t.styleCell = m
+	}
+}
+
+// compare a and b; nil values are always sorted first.
+func compare(a, b any, o Order) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch o {
+	case SortLexical:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	case SortNatural:
+		return compareNatural(fmt.Sprint(a), fmt.Sprint(b))
+	case SortNumeric:
+		if c, ok := compareNumbers(a, b); ok {
+			return c
+		}
+		fa, okA := parseFloat(a)
+		fb, okB := parseFloat(b)
+		switch {
+		case okA && okB:
+			return compareFloat(fa, fb)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	default:
+		if c, ok := compareNumbers(a, b); ok {
+			return c
+		}
+		if ta, ok := a.(time.Time); ok {
+			if tb, ok := b.(time.Time); ok {
+				switch {
+				case ta.Before(tb):
+					return -1
+				case ta.After(tb):
+					return 1
+				}
+				return 0
+			}
+		}
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
+
+// compareNumbers compares a and b if they're both an integer or float type.
+// Integers are compared as integers, so large values don't lose precision.
+func compareNumbers(a, b any) (int, bool) {
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	ka, kb := intKind(ra.Kind()), intKind(rb.Kind())
+	switch {
+	case ka == signed && kb == signed:
+		/// Flipping the sign bit keeps the order as uint64.
+		return compareUint(uint64(ra.Int())^1<<63, uint64(rb.Int())^1<<63), true
+	case ka == unsigned && kb == unsigned:
+		return compareUint(ra.Uint(), rb.Uint()), true
+	case ka == signed && kb == unsigned:
+		if ra.Int() < 0 {
+			return -1, true
+		}
+		return compareUint(uint64(ra.Int()), rb.Uint()), true
+	case ka == unsigned && kb == signed:
+		if rb.Int() < 0 {
+			return 1, true
+		}
+		return compareUint(ra.Uint(), uint64(rb.Int())), true
+	}
+
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	return compareFloat(fa, fb), true
+}
+
+const (
+	notInt = iota
+	signed
+	unsigned
+)
+
+func intKind(k reflect.Kind) int {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return signed
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unsigned
+	}
+	return notInt
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareNatural compares a and b as strings, except that sequences of digits
+// are compared as numbers.
+func compareNatural(a, b string) int {
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	for len(a) > 0 && len(b) > 0 {
+		if !isDigit(a[0]) || !isDigit(b[0]) {
+			if a[0] != b[0] {
+				return compareInt(int(a[0]), int(b[0]))
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		var i, j int
+		for i < len(a) && isDigit(a[i]) {
+			i++
+		}
+		for j < len(b) && isDigit(b[j]) {
+			j++
+		}
+		na, nb := strings.TrimLeft(a[:i], "0"), strings.TrimLeft(b[:j], "0")
+		if len(na) != len(nb) {
+			return compareInt(len(na), len(nb))
+		}
+		if c := strings.Compare(na, nb); c != 0 {
+			return c
+		}
+		a, b = a[i:], b[j:]
+	}
+	return compareInt(len(a), len(b))
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// toFloat converts any integer or float type to a float64.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// parseFloat is like toFloat, but also parses strings.
+func parseFloat(v any) (float64, bool) {
+	if f, ok := toFloat(v); ok {
+		return f, true
+	}
+	var s string
+	switch vv := v.(type) {
+	case string:
+		s = vv
+	case []byte:
+		s = string(vv)
+	default:
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil
+}
@@ -0,0 +1,138 @@
+package acidtab
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSortBy(t *testing.T) {
+	newTable := func() *Table {
+		return New("Name", "N", "File").Close(CloseLeft|CloseRight).
+			FormatColFunc(1, FormatAsNum()).
+			Rows(
+				"b", 1000, "file10",
+				"a", 9, "file9",
+				"c", 1000, "file1",
+				"a", 100, "File2",
+			)
+	}
+
+	tests := []struct {
+		keys []SortKey
+		want string
+	}{
+		{[]SortKey{{Col: 0}}, `
+			│  Name  │    N    │   File   │
+			├────────┼─────────┼──────────┤
+			│  a     │      9  │  file9   │
+			│  a     │    100  │  File2   │
+			│  b     │  1,000  │  file10  │
+			│  c     │  1,000  │  file1   │
+		`},
+		{[]SortKey{{Col: 1, Desc: true}, {Col: 0, Desc: true}}, `
+			│  Name  │    N    │   File   │
+			├────────┼─────────┼──────────┤
+			│  c     │  1,000  │  file1   │
+			│  b     │  1,000  │  file10  │
+			│  a     │    100  │  File2   │
+			│  a     │      9  │  file9   │
+		`},
+		{[]SortKey{{Col: 1, Order: SortLexical}}, `
+			│  Name  │    N    │   File   │
+			├────────┼─────────┼──────────┤
+			│  a     │    100  │  File2   │
+			│  b     │  1,000  │  file10  │
+			│  c     │  1,000  │  file1   │
+			│  a     │      9  │  file9   │
+		`},
+		{[]SortKey{{Col: 2, Order: SortNatural}}, `
+			│  Name  │    N    │   File   │
+			├────────┼─────────┼──────────┤
+			│  a     │    100  │  File2   │
+			│  c     │  1,000  │  file1   │
+			│  a     │      9  │  file9   │
+			│  b     │  1,000  │  file10  │
+		`},
+		{[]SortKey{{Col: 2, Cmp: func(a, b any) int {
+			return compareNatural(strings.ToLower(a.(string)), strings.ToLower(b.(string)))
+		}}}, `
+			│  Name  │    N    │   File   │
+			├────────┼─────────┼──────────┤
+			│  c     │  1,000  │  file1   │
+			│  a     │    100  │  File2   │
+			│  a     │      9  │  file9   │
+			│  b     │  1,000  │  file10  │
+		`},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.keys), func(t *testing.T) {
+			tbl := newTable().SortBy(tt.keys...)
+			if err := tbl.Error(); err != nil {
+				t.Fatal(err)
+			}
+			test(t, tbl.Horizontal, tt.want)
+		})
+	}
+
+	t.Run("large ints", func(t *testing.T) {
+		tbl := New("n").Rows(int64(1<<53+1), int64(1<<53), int64(1<<53+2)).SortBy(SortKey{Col: 0})
+		test(t, tbl.Horizontal, `
+			         n
+			────────────────────
+			  9007199254740992
+			  9007199254740993
+			  9007199254740994
+		`)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := newTable().SortBy(SortKey{Col: 3}).Error()
+		if !errorContains(err, "SortBy: cannot set column 3") {
+			t.Error(err)
+		}
+
+		err = newTable().SortBy(SortKey{Col: -1}).Error()
+		if !errorContains(err, "SortBy: cannot set negative column -1") {
+			t.Error(err)
+		}
+	})
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b any
+		o    Order
+		want int
+	}{
+		{nil, nil, SortAuto, 0},
+		{nil, 1, SortAuto, -1},
+		{1, nil, SortAuto, 1},
+		{2, 10, SortAuto, -1},
+		{int8(2), 1.5, SortAuto, 1},
+		{int64(1 << 53), int64(1<<53 + 1), SortAuto, -1},
+		{uint64(1<<53 + 1), uint64(1 << 53), SortAuto, 1},
+		{int64(1<<53 + 1), uint64(1 << 53), SortAuto, 1},
+		{uint64(1 << 63), int64(-1), SortAuto, 1},
+		{int64(-1), uint64(1 << 63), SortNumeric, -1},
+		{int64(-5), int64(3), SortAuto, -1},
+		{"2", "10", SortAuto, 1},
+		{"2", "10", SortNumeric, -1},
+		{"x", "10", SortNumeric, 1},
+		{"x", "y", SortNumeric, -1},
+		{2, 10, SortLexical, 1},
+		{"a2", "a10", SortNatural, -1},
+		{"a02", "a2", SortNatural, 0},
+		{"a2b", "a2a", SortNatural, 1},
+		{"a2", "a2b", SortNatural, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v-%v-%d", tt.a, tt.b, tt.o), func(t *testing.T) {
+			if have := compare(tt.a, tt.b, tt.o); have != tt.want {
+				t.Errorf("have %d; want %d", have, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package acidtab
+
+import (
+	"fmt"
+	"strings"
+
+	"zgo.at/termtext"
+)
+
+// Span is a cell that spans multiple columns.
+type Span struct {
+	Text string
+	Cols int // Number of columns; 0 for all remaining columns.
+}
+
+type spanRow struct {
+	at    int // Print before this row.
+	spans []Span
+}
+
+// colBorders are the borders after every column of a row; nil means all
+// columns have a border.
+type colBorders []bool
+
+// noBorders is a row without any borders between the columns.
+var noBorders = colBorders{}
+
+func (c colBorders) has(i int) bool { return c == nil || (i < len(c) && c[i]) }
+
+// HeaderSpan adds a header row with cells that span multiple columns, which is
+// printed above the regular header. For example:
+//
+//	t := acidtab.New("Host", "p50", "p95", "p99")
+//	t.HeaderSpan(acidtab.Span{Cols: 1}, acidtab.Span{Text: "Latency", Cols: 3})
+//
+// Any remaining columns are filled with empty cells. HeaderSpan can be called
+// more than once to add multiple levels. This only applies to Horizontal().
+func (t *Table) HeaderSpan(spans ...Span) *Table {
+	if t.checkSpans(spans, "HeaderSpan") {
+		t.headerSpans = append(t.headerSpans, spans)
+	}
+	return t
+}
+
+// SpanRow adds a row with cells that span multiple columns before the next row
+// that is added, for example for section headings:
+//
+//	t.SpanRow(acidtab.Span{Text: "Section"})
+//
+// The text is left-aligned and any remaining columns are filled with empty
+// cells. Vertical() prints the text of all cells on a single line.
+//
+// Span rows added after the last row are printed at the end of the table,
+// before the footer.
+//
+// Span rows aren't printed for tables with GroupBy().
+func (t *Table) SpanRow(spans ...Span) *Table {
+	if t.checkSpans(spans, "SpanRow") {
+		t.spanRows = append(t.spanRows, spanRow{at: len(t.rows), spans: spans})
+	}
+	return t
+}
+
+func (t *Table) checkSpans(spans []Span, f string) bool {
+	var n int
+	for _, s := range spans {
+		n += s.Cols
+		if s.Cols < 0 || n > len(t.header) {
+			t.err = fmt.Errorf("%s: spans cover more columns than the %d columns", f, len(t.header))
+			return false
+		}
+	}
+	return true
+}
+
+// spanRowsAt gets all span rows to print before row i.
+func (t Table) spanRowsAt(i int) [][]Span {
+	var spans [][]Span
+	for _, s := range t.spanRows {
+		if s.at == i {
+			spans = append(spans, s.spans)
+		}
+	}
+	return spans
+}
+
+// spanCells gets the text and number of columns for every cell in spans,
+// adding empty cells for any remaining columns.
+func (t Table) spanCells(spans []Span) ([]string, []int) {
+	var (
+		cells = make([]string, 0, len(spans))
+		cols  = make([]int, 0, len(spans))
+		n     int
+	)
+	for _, s := range spans {
+		c := s.Cols
+		if c == 0 || n+c > len(t.header) {
+			c = len(t.header) - n
+		}
+		if c <= 0 {
+			break
+		}
+		cells, cols = append(cells, s.Text), append(cols, c)
+		n += c
+	}
+	for ; n < len(t.header); n++ {
+		cells, cols = append(cells, ""), append(cols, 1)
+	}
+	return cells, cols
+}
+
+// spanItem gets the horiItem for a span row.
+func (t Table) spanItem(spans []Span, header, line bool) horiItem {
+	cells, cols := t.spanCells(spans)
+	borders := make(colBorders, len(t.header))
+	var n int
+	for _, c := range cols {
+		n += c
+		borders[n-1] = true
+	}
+	return horiItem{cells: cells, cols: cols, center: header, line: line, borders: borders}
+}
+
+// spanWidth gets the display width of c columns starting at column col,
+// including the borders and padding between them.
+func (t Table) spanWidth(widths []int, col, c int) int {
+	w := (c - 1) * (termtext.Width(t.pad)*2 + 1)
+	for i := col; i < col+c; i++ {
+		w += widths[i]
+	}
+	return w
+}
+
+// spanWidths widens the last column of every span in the header spans and
+// span rows if the text doesn't fit.
+func (t Table) spanWidths(widths []int) {
+	all := make([][]Span, 0, len(t.headerSpans)+len(t.spanRows))
+	if t.pHeader {
+		all = append(all, t.headerSpans...)
+	}
+	if !t.grouped {
+		for _, s := range t.spanRows {
+			all = append(all, s.spans)
+		}
+	}
+	for _, spans := range all {
+		cells, cols := t.spanCells(spans)
+		var col int
+		for i := range cells {
+			if l := textWidth(cells[i]) - t.spanWidth(widths, col, cols[i]); l > 0 {
+				widths[col+cols[i]-1] += l
+			}
+			col += cols[i]
+		}
+	}
+}
+
+// spanRow writes a row with cells spanning cols columns. Cells are centered and
+// use the header style if header is set, and are left-aligned otherwise.
+func (t Table) spanRow(b writer, cells []string, cols []int, header bool) {
+	a, st := Left, Style{}
+	if header {
+		a, st = Center, t.headerStyle
+	}
+	var (
+		lines  = make([][]string, len(cells))
+		widths = make([]int, len(cells))
+		height = 1
+		col    int
+	)
+	for i := range cells {
+		widths[i] = t.spanWidth(t.widths, col, cols[i])
+		lines[i] = t.cellLines(col, cells[i], widths[i])
+		if len(lines[i]) > height {
+			height = len(lines[i])
+		}
+		col += cols[i]
+	}
+
+	for l := 0; l < height; l++ {
+		b.WriteString(t.prefix)
+		if t.close&CloseLeft != 0 {
+			b.WriteString(t.bar())
+		}
+		for i := range cells {
+			var cell string
+			if l < len(lines[i]) {
+				cell = lines[i][l]
+			}
+			trail := t.close&CloseRight != 0 || i != len(cells)-1
+			b.WriteString(t.pad)
+			if st != (Style{}) {
+				sb := new(strings.Builder)
+				writeAligned(sb, cell, widths[i], a, trail)
+				b.WriteString(st.apply(sb.String()))
+			} else {
+				writeAligned(b, cell, widths[i], a, trail)
+			}
+			if trail {
+				b.WriteString(t.pad)
+				b.WriteString(t.bar())
+			}
+		}
+		b.WriteByte('\n')
+	}
+}
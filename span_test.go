@@ -0,0 +1,115 @@
+package acidtab
+
+import "testing"
+
+func TestSpan(t *testing.T) {
+	tbl := New("Host", "p50", "p95", "p99").
+		Close(CloseAll).
+		HeaderSpan(Span{Cols: 1}, Span{Text: "Latency (ms)", Cols: 3}).
+		SpanRow(Span{Text: "Europe"}).
+		Row("a.example.com", 4, 12, 40).
+		Row("b.example.com", 5, 10, 31).
+		SpanRow(Span{Text: "Asia"}).
+		Row("c.example.com", 8, 20, 95)
+
+	test(t, tbl.Horizontal, `
+		┌─────────────────┬───────────────────────┐
+		│                 │     Latency (ms)      │
+		├─────────────────┼───────┬───────┬───────┤
+		│      Host       │  p50  │  p95  │  p99  │
+		├─────────────────┴───────┴───────┴───────┤
+		│  Europe                                 │
+		├─────────────────┬───────┬───────┬───────┤
+		│  a.example.com  │    4  │   12  │   40  │
+		│  b.example.com  │    5  │   10  │   31  │
+		├─────────────────┴───────┴───────┴───────┤
+		│  Asia                                   │
+		├─────────────────┬───────┬───────┬───────┤
+		│  c.example.com  │    8  │   20  │   95  │
+		└─────────────────┴───────┴───────┴───────┘
+	`)
+
+	tbl.SortBy(SortKey{Col: 3})
+	test(t, tbl.Vertical, `
+		┌──────────────────────────┐
+		│  Europe                  │
+		├────────┬─────────────────┤
+		│  Host  │  b.example.com  │
+		│  p50   │  5              │
+		│  p95   │  10             │
+		│  p99   │  31             │
+		├────────┼─────────────────┤
+		│  Host  │  a.example.com  │
+		│  p50   │  4              │
+		│  p95   │  12             │
+		│  p99   │  40             │
+		├────────┴─────────────────┤
+		│  Asia                    │
+		├────────┬─────────────────┤
+		│  Host  │  c.example.com  │
+		│  p50   │  8              │
+		│  p95   │  20             │
+		│  p99   │  95             │
+		└────────┴─────────────────┘
+	`)
+
+	tbl = New("a", "b", "c").
+		HeaderSpan(Span{Text: "wide header span", Cols: 2}, Span{Text: "x"}).
+		Row("1", "2", "3")
+	test(t, tbl.Horizontal, `
+		  wide header span  │  x
+		─────┬──────────────┼─────
+		  a  │      b       │  c
+		─────┼──────────────┼─────
+		  1  │  2           │  3
+	`)
+
+	// Span row after the last row.
+	tbl = New("a", "b").Close(CloseAll).
+		Row("1", "2").
+		SpanRow(Span{Text: "end"})
+	test(t, tbl.Horizontal, `
+		┌─────┬─────┐
+		│  a  │  b  │
+		├─────┼─────┤
+		│  1  │  2  │
+		├─────┴─────┤
+		│  end      │
+		└───────────┘
+	`)
+	test(t, tbl.Vertical, `
+		┌─────┬─────┐
+		│  a  │  1  │
+		│  b  │  2  │
+		├─────┴─────┤
+		│  end      │
+		└───────────┘
+	`)
+	tbl.Footer("x", "y")
+	test(t, tbl.Horizontal, `
+		┌─────┬─────┐
+		│  a  │  b  │
+		├─────┼─────┤
+		│  1  │  2  │
+		├─────┴─────┤
+		│  end      │
+		├─────┬─────┤
+		│  x  │  y  │
+		└─────┴─────┘
+	`)
+	test(t, tbl.Vertical, `
+		┌─────┬─────┐
+		│  a  │  1  │
+		│  b  │  2  │
+		├─────┴─────┤
+		│  end      │
+		├─────┬─────┤
+		│  a  │  x  │
+		│  b  │  y  │
+		└─────┴─────┘
+	`)
+
+	if err := New("a", "b").HeaderSpan(Span{Cols: 3}).Error(); err == nil {
+		t.Error("no error")
+	}
+}
@@ -0,0 +1,175 @@
+package acidtab
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type structField struct {
+	index  []int
+	name   string
+	align  Align
+	format FormatAs
+}
+
+// FromStructs creates a new table from a slice of structs.
+//
+// All exported fields are added as columns, with the field name as the header.
+// Fields of embedded structs are added as if they're fields of the outer
+// struct. Both the slice and the elements can be pointers; nil elements are
+// skipped, and pointer fields are dereferenced.
+//
+// The acidtab struct tag can be used to set the header and column options:
+//
+//	Name string `acidtab:"Full name,align=right,format=%q"`
+//
+// The first value is the header (the field name is used if it's empty). The
+// align option sets the alignment (left, right, center, or auto), and format
+// sets the fmt format string (which can't contain a comma). Use "-" or the omit
+// option to skip a field.
+//
+// An error will be set if slice is not a slice of structs.
+func FromStructs(slice any) *Table {
+	t := New()
+
+	v := reflect.ValueOf(slice)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		t.err = fmt.Errorf("FromStructs: not a slice but %T", slice)
+		return t
+	}
+	typ := v.Type().Elem()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		t.err = fmt.Errorf("FromStructs: not a slice of structs but %T", slice)
+		return t
+	}
+
+	fields, err := structFields(typ, nil)
+	if err != nil {
+		t.err = fmt.Errorf("FromStructs: %w", err)
+		return t
+	}
+
+	header := make([]string, 0, len(fields))
+	for _, f := range fields {
+		header = append(header, f.name)
+	}
+	t.Header(true, header...)
+	for i, f := range fields {
+		t.align[i] = f.align
+		if f.format != "" {
+			t.printAs[i] = f.format
+		}
+	}
+
+	t.Grow(v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		for elem.Kind() == reflect.Pointer && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Pointer {
+			continue
+		}
+
+		row := make([]any, 0, len(fields))
+		for _, f := range fields {
+			row = append(row, fieldValue(elem, f.index))
+		}
+		t.Row(row...)
+	}
+	return t
+}
+
+func structFields(typ reflect.Type, index []int) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := structField{
+			index: append(append(make([]int, 0, len(index)+1), index...), i),
+			name:  sf.Name,
+		}
+
+		tag, hasTag := sf.Tag.Lookup("acidtab")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		if opts[0] != "" {
+			f.name = opts[0]
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && ft.Kind() == reflect.Struct && !hasTag {
+			embed, err := structFields(ft, f.index)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embed...)
+			continue
+		}
+
+		omit := false
+		for _, o := range opts[1:] {
+			k, v, _ := strings.Cut(o, "=")
+			switch strings.TrimSpace(k) {
+			default:
+				return nil, fmt.Errorf("field %s: unknown option %q in tag %q", sf.Name, o, tag)
+			case "omit":
+				omit = true
+			case "format":
+				f.format = FormatAs(v)
+			case "align":
+				switch v {
+				default:
+					return nil, fmt.Errorf("field %s: unknown alignment %q in tag %q", sf.Name, v, tag)
+				case "auto":
+					f.align = Auto
+				case "left":
+					f.align = Left
+				case "right":
+					f.align = Right
+				case "center":
+					f.align = Center
+				}
+			}
+		}
+		if !omit {
+			fields = append(fields, f)
+		}
+	}
+	return fields, nil
+}
+
+// fieldValue gets the value of the field at index, dereferencing any pointers.
+// It returns nil if a nil pointer is encountered.
+func fieldValue(v reflect.Value, index []int) any {
+	for _, i := range index {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
@@ -0,0 +1,65 @@
+package acidtab
+
+import "testing"
+
+func TestFromStructs(t *testing.T) {
+	type (
+		Base struct {
+			ID int `acidtab:"#"`
+		}
+		Extra  struct{ Note string }
+		Person struct {
+			Base
+			*Extra
+			Name    string `acidtab:",align=center"`
+			Origin  *string
+			Job     string `acidtab:"Occupation,format=%q"`
+			Secret  string `acidtab:"-"`
+			Skipped bool   `acidtab:"Skip,omit"`
+			private int
+		}
+	)
+
+	earth := "Earth"
+	tbl := FromStructs(&[]*Person{
+		{Base: Base{1}, Name: "Chrisjen Avasarala", Origin: &earth, Job: "Politician", Extra: &Extra{"Insults"}},
+		nil,
+		{Base: Base{2}, Name: "Prax Meng", Job: "Botanist"},
+	}).Close(CloseLeft | CloseRight)
+	if err := tbl.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	test(t, tbl.Horizontal, `
+		│  #  │   Note    │         Name         │  Origin  │   Occupation   │
+		├─────┼───────────┼──────────────────────┼──────────┼────────────────┤
+		│  1  │  Insults  │  Chrisjen Avasarala  │  Earth   │  "Politician"  │
+		│  2  │  <nil>    │      Prax Meng       │  <nil>   │  "Botanist"    │
+	`)
+}
+
+func TestFromStructsErrors(t *testing.T) {
+	tests := []struct {
+		in      any
+		wantErr string
+	}{
+		{[]struct{ A int }{}, ""},
+		{nil, "not a slice but <nil>"},
+		{"x", "not a slice but string"},
+		{[]int{1}, "not a slice of structs but []int"},
+		{[]struct {
+			A int `acidtab:",x"`
+		}{}, `field A: unknown option "x"`},
+		{[]struct {
+			A int `acidtab:",align=up"`
+		}{}, `field A: unknown alignment "up"`},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			if have := FromStructs(tt.in).Error(); !errorContains(have, tt.wantErr) {
+				t.Errorf("wrong error\nwant: %s\nhave: %s", tt.wantErr, have)
+			}
+		})
+	}
+}
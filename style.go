@@ -0,0 +1,327 @@
+package acidtab
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Color is a terminal colour; the zero value is the terminal's default
+	// colour.
+	Color uint32
+
+	// Style is a text style.
+	Style struct {
+		Fg, Bg                       Color
+		Bold, Dim, Italic, Underline bool
+	}
+
+	// ColorMode sets when to print colours and other text attributes.
+	ColorMode uint8
+)
+
+// When to print colours.
+const (
+	// Always print colours.
+	ColorAlways ColorMode = iota
+
+	// Print colours if the writer is a terminal. The NO_COLOR environment
+	// variable disables colours and FORCE_COLOR enables them, if set to a
+	// non-empty value. FORCE_COLOR=0 disables colours.
+	ColorAuto
+
+	// Never print colours.
+	ColorNever
+)
+
+// Kind of colour, stored in the highest byte of Color.
+const (
+	color16  Color = 1 << 24
+	color256 Color = 2 << 24
+	colorRGB Color = 3 << 24
+)
+
+// The 16 standard terminal colours; how they look depends on the terminal.
+const (
+	ColorBlack Color = color16 + iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+	ColorBrightBlack
+	ColorBrightRed
+	ColorBrightGreen
+	ColorBrightYellow
+	ColorBrightBlue
+	ColorBrightMagenta
+	ColorBrightCyan
+	ColorBrightWhite
+)
+
+// Color256 gets colour n from the 256-colour palette.
+func Color256(n uint8) Color { return color256 | Color(n) }
+
+// RGB gets a 24-bit "truecolor" colour.
+func RGB(r, g, b uint8) Color { return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b) }
+
+// sgr gets the SGR parameters to set c as the foreground or background colour.
+func (c Color) sgr(bg bool) string {
+	n := int(c & 0xffffff)
+	switch c &^ 0xffffff {
+	case color16:
+		switch {
+		case n < 8 && bg:
+			return strconv.Itoa(40 + n)
+		case n < 8:
+			return strconv.Itoa(30 + n)
+		case bg:
+			return strconv.Itoa(100 + n - 8)
+		default:
+			return strconv.Itoa(90 + n - 8)
+		}
+	case color256:
+		if bg {
+			return "48;5;" + strconv.Itoa(n)
+		}
+		return "38;5;" + strconv.Itoa(n)
+	case colorRGB:
+		p := "38;2;"
+		if bg {
+			p = "48;2;"
+		}
+		return fmt.Sprintf("%s%d;%d;%d", p, n>>16, n>>8&0xff, n&0xff)
+	}
+	return ""
+}
+
+// css gets the CSS colour for c, or "" for the default colour.
+func (c Color) css() string {
+	n := int(c & 0xffffff)
+	switch c &^ 0xffffff {
+	case color16, color256:
+		return xtermColor(n)
+	case colorRGB:
+		return fmt.Sprintf("#%06x", n)
+	}
+	return ""
+}
+
+// merge s with o; the colours in o are used if they're set, and the text
+// attributes are combined.
+func (s Style) merge(o Style) Style {
+	if o.Fg != 0 {
+		s.Fg = o.Fg
+	}
+	if o.Bg != 0 {
+		s.Bg = o.Bg
+	}
+	s.Bold, s.Dim = s.Bold || o.Bold, s.Dim || o.Dim
+	s.Italic, s.Underline = s.Italic || o.Italic, s.Underline || o.Underline
+	return s
+}
+
+// sgr gets the SGR escape sequence for the style, or "" if there is no style.
+func (s Style) sgr() string {
+	p := make([]string, 0, 6)
+	if s.Bold {
+		p = append(p, "1")
+	}
+	if s.Dim {
+		p = append(p, "2")
+	}
+	if s.Italic {
+		p = append(p, "3")
+	}
+	if s.Underline {
+		p = append(p, "4")
+	}
+	if s.Fg != 0 {
+		p = append(p, s.Fg.sgr(false))
+	}
+	if s.Bg != 0 {
+		p = append(p, s.Bg.sgr(true))
+	}
+	if len(p) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(p, ";") + "m"
+}
+
+// apply the style to text. The style is set again after any resets in the
+// text, so that colours added with FormatColFunc() don't clear it.
+func (s Style) apply(text string) string {
+	sgr := s.sgr()
+	if sgr == "" || text == "" {
+		return text
+	}
+	text = strings.ReplaceAll(text, "\x1b[0m", "\x1b[0m"+sgr)
+	text = strings.ReplaceAll(text, "\x1b[m", "\x1b[m"+sgr)
+	return sgr + text + "\x1b[0m"
+}
+
+// css gets the style as CSS declarations.
+func (s Style) css() string {
+	var css []string
+	if s.Bold {
+		css = append(css, "font-weight: bold")
+	}
+	if s.Dim {
+		css = append(css, "opacity: .6")
+	}
+	if s.Italic {
+		css = append(css, "font-style: italic")
+	}
+	if s.Underline {
+		css = append(css, "text-decoration: underline")
+	}
+	if c := s.Fg.css(); c != "" {
+		css = append(css, "color: "+c)
+	}
+	if c := s.Bg.css(); c != "" {
+		css = append(css, "background-color: "+c)
+	}
+	return strings.Join(css, "; ")
+}
+
+// Colors sets when to print colours and text attributes in Horizontal() and
+// Vertical(). The default is ColorAlways.
+//
+// If colours are disabled all SGR escape sequences are removed, including
+// those added with FormatColFunc() and in the header.
+func (t *Table) Colors(m ColorMode) *Table { t.colors = m; return t }
+
+// useColor reports if colours should be printed to w.
+func (t Table) useColor(w io.Writer) bool {
+	switch t.colors {
+	case ColorNever:
+		return false
+	case ColorAuto:
+		if os.Getenv("NO_COLOR") != "" {
+			return false
+		}
+		if f := os.Getenv("FORCE_COLOR"); f != "" {
+			return f != "0" && f != "false"
+		}
+		f, ok := w.(*os.File)
+		return ok && isTerminal(f)
+	}
+	return true
+}
+
+// StyleHeader sets the style for all header cells. This is combined with the
+// StyleHeaderCol() style, using the colours from StyleHeaderCol() if they're
+// set.
+func (t *Table) StyleHeader(s Style) *Table { t.headerStyle = s; return t }
+
+// StyleBorders sets the style for all border characters, for example to dim
+// them.
+func (t *Table) StyleBorders(s Style) *Table { t.borderStyle = s; return t }
+
+// StyleCol sets the style for all cells in column n, including the footer.
+func (t *Table) StyleCol(n int, s Style) *Table {
+	if t.checkN(n, "StyleCol") {
+		t.styleCol[n] = s
+	}
+	return t
+}
+
+// StyleHeaderCol sets the style for the header of column n.
+func (t *Table) StyleHeaderCol(n int, s Style) *Table {
+	if t.checkN(n, "StyleHeaderCol") {
+		t.styleHeader[n] = s
+	}
+	return t
+}
+
+// StyleRow sets the style for all cells in row n, which must already be added.
+// This is combined with the StyleCol() style, using the colours from this style
+// if they're set.
+func (t *Table) StyleRow(n int, s Style) *Table {
+	if t.checkRow(n, "StyleRow") {
+		if t.styleRow == nil {
+			t.styleRow = make(map[int]Style)
+		}
+		t.styleRow[n] = s
+	}
+	return t
+}
+
+// StyleCell sets the style for a single cell in row n, column col. This is
+// combined with the StyleCol() and StyleRow() styles.
+func (t *Table) StyleCell(n, col int, s Style) *Table {
+	if t.checkRow(n, "StyleCell") && t.checkN(col, "StyleCell") {
+		if t.styleCell == nil {
+			t.styleCell = make(map[[2]int]Style)
+		}
+		t.styleCell[[2]int{n, col}] = s
+	}
+	return t
+}
+
+func (t *Table) checkRow(n int, f string) bool {
+	if n < 0 || n > len(t.rows)-1 {
+		t.err = fmt.Errorf("%s: cannot set row %d as there are only %d rows", f, n, len(t.rows))
+		return false
+	}
+	return true
+}
+
+// StripeRows sets styles to use for alternating rows, for example to give every
+// other row a different background colour:
+//
+//	t.StripeRows(acidtab.Style{}, acidtab.Style{Bg: acidtab.Color256(236)})
+//
+// The styles are used in the order the rows are printed, and aren't used for
+// the subtotals and footer. They're combined with the other styles like
+// StyleRow(), which take precedence.
+func (t *Table) StripeRows(styles ...Style) *Table { t.stripes = styles; return t }
+
+// HighlightRows sets a function to get the style for a row from the values as
+// passed to Row(); for example to show failed rows in red:
+//
+//	t.HighlightRows(func(row []any) acidtab.Style {
+//	    if row[2] == "failed" {
+//	        return acidtab.Style{Fg: acidtab.ColorRed}
+//	    }
+//	    return acidtab.Style{}
+//	})
+//
+// The row may have fewer values than there are columns. This is combined with
+// the StripeRows() style, using the colours from this style if they're set,
+// and StyleRow() and StyleCell() take precedence.
+func (t *Table) HighlightRows(f func(row []any) Style) *Table { t.highlight = f; return t }
+
+// cellStyles gets the styles for every cell in row n; use -1 to get the styles
+// for rows that aren't added with Row(), such as the footer. The stripe is the
+// position of the row for StripeRows(), or -1 to not use it.
+func (t Table) cellStyles(n, stripe int) []Style {
+	if len(t.styleRow) == 0 && len(t.styleCell) == 0 && len(t.stripes) == 0 &&
+		t.highlight == nil && (n < 0 || t.heatRange == nil) {
+		return t.styleCol
+	}
+
+	var rs Style
+	if stripe >= 0 && len(t.stripes) > 0 {
+		rs = t.stripes[stripe%len(t.stripes)]
+	}
+	if t.highlight != nil && n >= 0 {
+		rs = rs.merge(t.highlight(t.raw[n]))
+	}
+	rs = rs.merge(t.styleRow[n])
+
+	styles := make([]Style, len(t.header))
+	for i := range styles {
+		styles[i] = t.styleCol[i]
+		if n >= 0 && i < len(t.raw[n]) {
+			styles[i] = styles[i].merge(t.heatStyle(i, t.raw[n][i]))
+		}
+		styles[i] = styles[i].merge(rs).merge(t.styleCell[[2]int{n, i}])
+	}
+	return styles
+}
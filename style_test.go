@@ -0,0 +1,182 @@
+package acidtab
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStyleSGR(t *testing.T) {
+	tests := []struct {
+		in   Style
+		want string
+	}{
+		{Style{}, ""},
+		{Style{Bold: true}, "\x1b[1m"},
+		{Style{Fg: ColorRed, Bg: ColorBrightBlue}, "\x1b[31;104m"},
+		{Style{Dim: true, Italic: true, Underline: true, Fg: ColorBrightBlack, Bg: ColorWhite}, "\x1b[2;3;4;90;47m"},
+		{Style{Fg: Color256(196), Bg: RGB(1, 2, 255)}, "\x1b[38;5;196;48;2;1;2;255m"},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			if have := tt.in.sgr(); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyle(t *testing.T) {
+	tbl := New("Name", "Status").
+		Close(CloseLeft|CloseRight).
+		Rows(
+			"b", "\x1b[32mok\x1b[0m",
+			"a", "failed").
+		StyleHeaderCol(0, Style{Bold: true}).
+		StyleCol(1, Style{Fg: ColorRed}).
+		StyleRow(0, Style{Bg: ColorBlue}).
+		StyleCell(1, 1, Style{Fg: ColorGreen, Underline: true}).
+		SortBy(SortKey{Col: 0})
+
+	test(t, tbl.Horizontal, `
+		│  `+"\x1b[1mName\x1b[0m"+`  │  Status  │
+		├────────┼──────────┤
+		│  a     │  `+"\x1b[4;32mfailed\x1b[0m"+`  │
+		│  `+"\x1b[44mb   \x1b[0m"+`  │  `+"\x1b[31;44m\x1b[32mok\x1b[0m\x1b[31;44m    \x1b[0m"+`  │
+	`)
+
+	test(t, tbl.Vertical, `
+		│  `+"\x1b[1mName\x1b[0m"+`    │  a       │
+		│  Status  │  `+"\x1b[4;32mfailed\x1b[0m"+`  │
+		├──────────┼──────────┤
+		│  `+"\x1b[1mName\x1b[0m"+`    │  `+"\x1b[44mb     \x1b[0m"+`  │
+		│  Status  │  `+"\x1b[31;44m\x1b[32mok\x1b[0m\x1b[31;44m    \x1b[0m"+`  │
+	`)
+
+	test(t, tbl.HTML, `
+		<table>
+		<thead>
+		<tr><th style="text-align: left; font-weight: bold">Name</th><th style="text-align: left">Status</th></tr>
+		</thead>
+		<tbody>
+		<tr><td style="text-align: left">a</td><td style="text-align: left; text-decoration: underline; color: #00cd00">failed</td></tr>
+		<tr><td style="text-align: left; background-color: #0000ee">b</td><td style="text-align: left; color: #cd0000; background-color: #0000ee">ok</td></tr>
+		</tbody>
+		</table>
+	`)
+
+	if err := tbl.StyleRow(2, Style{}).Error(); err == nil {
+		t.Error("no error")
+	}
+}
+
+func TestColors(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+
+	tbl := New("\x1b[1mName\x1b[0m", "Status").
+		Row("a", "\x1b[32mok\x1b[0m").
+		StyleCol(0, Style{Fg: ColorRed})
+
+	tests := []struct {
+		mode        ColorMode
+		noColor     string
+		forceColor  string
+		wantColored bool
+	}{
+		{ColorAlways, "1", "", true},
+		{ColorNever, "", "1", false},
+		{ColorAuto, "", "", false}, // Not a terminal.
+		{ColorAuto, "", "1", true},
+		{ColorAuto, "", "0", false},
+		{ColorAuto, "1", "1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+			tbl.Colors(tt.mode)
+
+			want := `
+				  Name  │  Status
+				────────┼──────────
+				  a     │  ok
+			`
+			if tt.wantColored {
+				want = `
+				  ` + "\x1b[1mName\x1b[0m" + `  │  Status
+				────────┼──────────
+				  ` + "\x1b[31ma   \x1b[0m" + `  │  ` + "\x1b[32mok\x1b[0m" + `
+				`
+			}
+			test(t, tbl.Horizontal, want)
+
+			b := new(strings.Builder)
+			tbl.Vertical(b)
+			if have := strings.Contains(b.String(), "\x1b["); have != tt.wantColored {
+				t.Errorf("colours in Vertical(): %t", have)
+			}
+		})
+	}
+}
+
+func TestStyleHeaderBorders(t *testing.T) {
+	tbl := New("Name", "Job").
+		Close(CloseAll).
+		StyleHeader(Style{Bold: true}).
+		StyleHeaderCol(1, Style{Fg: ColorBlue}).
+		StyleBorders(Style{Dim: true}).
+		Row("Holden", "Captain")
+
+	test(t, tbl.Horizontal, `
+		`+"\x1b[2m┌──────────┬───────────┐\x1b[0m"+`
+		`+"\x1b[2m│\x1b[0m  \x1b[1m Name \x1b[0m  \x1b[2m│\x1b[0m  \x1b[1;34m  Job  \x1b[0m  \x1b[2m│\x1b[0m"+`
+		`+"\x1b[2m├──────────┼───────────┤\x1b[0m"+`
+		`+"\x1b[2m│\x1b[0m  Holden  \x1b[2m│\x1b[0m  Captain  \x1b[2m│\x1b[0m"+`
+		`+"\x1b[2m└──────────┴───────────┘\x1b[0m"+`
+	`)
+
+	test(t, tbl.Vertical, `
+		`+"\x1b[2m┌────────┬───────────┐\x1b[0m"+`
+		`+"\x1b[2m│\x1b[0m  \x1b[1mName\x1b[0m  \x1b[2m│\x1b[0m  Holden   \x1b[2m│\x1b[0m"+`
+		`+"\x1b[2m│\x1b[0m  \x1b[1;34mJob\x1b[0m   \x1b[2m│\x1b[0m  Captain  \x1b[2m│\x1b[0m"+`
+		`+"\x1b[2m└────────┴───────────┘\x1b[0m"+`
+	`)
+}
+
+func TestStripeHighlight(t *testing.T) {
+	tbl := New("Name", "Status").
+		Rows(
+			"a", "ok",
+			"b", "failed",
+			"c", "ok",
+			"d", "ok").
+		StripeRows(Style{}, Style{Bg: ColorBlack}).
+		HighlightRows(func(row []any) Style {
+			if row[1] == "failed" {
+				return Style{Fg: ColorRed}
+			}
+			return Style{}
+		}).
+		StyleRow(3, Style{Bold: true})
+
+	test(t, tbl.Horizontal, `
+		  Name  │  Status
+		────────┼──────────
+		  a     │  ok
+		  `+"\x1b[31;40mb   \x1b[0m"+`  │  `+"\x1b[31;40mfailed\x1b[0m"+`
+		  c     │  ok
+		  `+"\x1b[1;40md   \x1b[0m"+`  │  `+"\x1b[1;40mok\x1b[0m"+`
+	`)
+
+	tbl.SortBy(SortKey{Col: 1})
+	test(t, tbl.Horizontal, `
+		  Name  │  Status
+		────────┼──────────
+		  `+"\x1b[31mb   \x1b[0m"+`  │  `+"\x1b[31mfailed\x1b[0m"+`
+		  `+"\x1b[40ma   \x1b[0m"+`  │  `+"\x1b[40mok\x1b[0m"+`
+		  c     │  ok
+		  `+"\x1b[1;40md   \x1b[0m"+`  │  `+"\x1b[1;40mok\x1b[0m"+`
+	`)
+}
@@ -4,6 +4,7 @@ package acidtab
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"zgo.at/termtext"
 )
@@ -13,6 +14,8 @@ type (
 	Align        uint8  // Alignment for columns.
 	FormatAs     string // How to print a value; fmt format string (e.g. "%q", "%#v", etc.)
 	FormatAsFunc func(v any) string
+	Overflow     uint8 // What to do with text that doesn't fit in a column.
+	Truncate     uint8 // Where to truncate text.
 
 	// Borders to use.
 	Borders struct {
@@ -40,36 +43,97 @@ var (
 	BordersSpace   = Borders{' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' '}
 )
 
+// What to do with text that doesn't fit in a column.
+const (
+	OverflowTruncate Overflow = iota // Truncate and add "…".
+	OverflowWrap                     // Wrap on multiple lines.
+)
+
+// Where to truncate text.
+const (
+	TruncateEnd    Truncate = iota // "Some long t…"
+	TruncateStart                  // "…e long text"
+	TruncateMiddle                 // "Some l…text"
+)
+
 // Column alignment.
 const (
 	Auto Align = iota
 	Left
 	Right
 	Center
+	Decimal // Align numbers on the decimal separator; also see DecimalSep().
 )
 
 // Table defines a table to print.
 type Table struct {
 	header []string
-	rows   [][]string
+	rows   [][]string // Formatted cells.
+	raw    [][]any    // Original values as passed to Row().
+	footer [][]any    // Footer rows, as passed to Footer().
+	seps   []int      // Print a line before these rows.
 	widths []int
 
+	headerSpans [][]Span  // Header rows above the header.
+	spanRows    []spanRow // Rows with spanning cells.
+
+	styleRow  map[int]Style    // Style for rows, by row index.
+	styleCell map[[2]int]Style // Style for cells, by row and column index.
+	colors    ColorMode
+
+	headerStyle Style             // Style for all header cells.
+	borderStyle Style             // Style for the borders.
+	stripes     []Style           // Styles for alternating rows.
+	highlight   func([]any) Style // Get style for a row.
+
 	close   Close   // Which sides to close?
 	borders Borders // Border characters to use.
 	pad     string  // Padding between columns, before and after.
 	prefix  string  // Print before every line.
 	pHeader bool    // Print header?
 
+	sepRows    bool // Print a line between every row?
+	sepRowsSet bool // SeparateRows() was used; Vertical() separates by default.
+
+	grouped   bool        // Group rows with GroupBy()?
+	groupCol  int         // Column to group by.
+	groupAggs []Aggregate // Aggregates for the subtotals.
+	groupHide bool        // Hide repeated values in groupCol.
+
+	maxWidth int      // Maximum width; 0 for no maximum.
+	overflow Overflow // What to do with text that doesn't fit.
+	ellipsis string   // Indicate text was truncated.
+	decSep   rune     // Decimal separator for Decimal alignment.
+
+	title, caption           string // Print above and below the table.
+	titleAlign, captionAlign Align
+
+	stripEsc  bool // Strip escapes in CSV, Markdown, etc.
+	escToHTML bool // Convert escapes to <span>s in HTML.
+
 	printAs  []FormatAs // Printf format verb; defaults to %v
 	printAsF []FormatAsFunc
 	align    []Align
+	minWidth []int // Minimum width for MaxWidth(); 0 for the default.
+	priority []int // Priority for MaxWidth(); lower is shrunk first.
+	wrap     []int // Wrap cells at this width; 0 to not wrap.
+	trunc    []int // Truncate cells at this width; 0 to not truncate.
+	truncAt  []Truncate
+
+	styleCol    []Style
+	styleHeader []Style
+	heatmap     []*Heatmap
+	bars        []int        // Print numbers as bars this wide; 0 to not print bars.
+	heatRange   [][2]float64 // Calculated when printing.
+	intWidth    []int        // Width of the integer part for Decimal alignment.
+	fracWidth   []int        // Width of the fraction, including the separator.
 
 	err error
 }
 
 // New creates a new table with the given headers.
 func New(header ...string) *Table {
-	t := &Table{pad: "  ", borders: BordersDefault}
+	t := &Table{pad: "  ", borders: BordersDefault, ellipsis: "…", decSep: '.'}
 	return t.Header(true, header...)
 }
 
@@ -91,13 +155,125 @@ func (t *Table) Prefix(prefix string) *Table { t.prefix = prefix; return t }
 // Borders sets the characters to use for borders
 func (t *Table) Borders(borders Borders) *Table { t.borders = borders; return t }
 
+// StripEscapes sets if terminal escape sequences are removed from the header
+// and cells when writing CSV(), TSV(), and Markdown().
+//
+// This is useful if you use FormatColFunc() to add colours, which you usually
+// don't want in these formats. Horizontal() and Vertical() are not affected.
+func (t *Table) StripEscapes(strip bool) *Table { t.stripEsc = strip; return t }
+
+// EscapesToHTML sets if SGR escape sequences (bold, colours, etc.) are
+// converted to <span> elements with a style attribute in HTML().
+//
+// The default is to remove all escape sequences.
+func (t *Table) EscapesToHTML(convert bool) *Table { t.escToHTML = convert; return t }
+
+// MaxWidth sets the maximum display width of the table, including the prefix
+// and borders. Use 0 to not set a maximum, which is the default.
+//
+// If the table is wider then columns are shrunk until it fits; the widest
+// columns are shrunk first, but columns with a higher priority set with
+// PriorityCol() are only shrunk after all columns with a lower priority are at
+// their minimum width (set with MinWidthCol()). Text that doesn't fit is
+// truncated or wrapped as set with Overflow().
+//
+// This only applies to Horizontal(). TerminalWidth() can be used to get the
+// width of the terminal.
+func (t *Table) MaxWidth(n int) *Table { t.maxWidth = n; return t }
+
+// Overflow sets what to do with text that doesn't fit in a column after it was
+// shrunk by MaxWidth(). The default is OverflowTruncate.
+//
+// This is ignored for columns with WrapCol() or TruncateCol().
+func (t *Table) Overflow(o Overflow) *Table { t.overflow = o; return t }
+
+// Ellipsis sets the text to indicate that text was truncated. The default is
+// "…".
+func (t *Table) Ellipsis(e string) *Table { t.ellipsis = e; return t }
+
+// SeparateRows sets if a line is printed between every row. The default is
+// false for Horizontal() and true for Vertical().
+func (t *Table) SeparateRows(sep bool) *Table {
+	t.sepRows, t.sepRowsSet = sep, true
+	return t
+}
+
+// Separator adds a line after the last row that was added, for example to
+// separate groups of rows. It has no effect if there are no rows yet.
+//
+// Vertical() prints a line between every row, unless SeparateRows(false) is
+// used, in which case it only prints a line for separators.
+func (t *Table) Separator() *Table {
+	n := len(t.rows)
+	if n > 0 && (len(t.seps) == 0 || t.seps[len(t.seps)-1] != n) {
+		t.seps = append(t.seps, n)
+	}
+	return t
+}
+
+// Title sets a title to print above the table.
+//
+// The title is printed inside the borders if the table is closed on all sides
+// with CloseAll, and as a plain line otherwise. Titles that are wider than the
+// table are wrapped.
+func (t *Table) Title(title string) *Table { t.title = title; return t }
+
+// Caption sets a caption to print below the table; this works like Title().
+func (t *Table) Caption(caption string) *Table { t.caption = caption; return t }
+
+// AlignTitle sets the alignment for the title. The default is to center it.
+func (t *Table) AlignTitle(a Align) *Table { t.titleAlign = a; return t }
+
+// AlignCaption sets the alignment for the caption. The default is to center it.
+func (t *Table) AlignCaption(a Align) *Table { t.captionAlign = a; return t }
+
+// MinWidthCol sets the minimum width column n can be shrunk to by MaxWidth().
+//
+// The default is 3, or the column width if that's smaller.
+func (t *Table) MinWidthCol(n, w int) *Table {
+	if t.checkN(n, "MinWidthCol") {
+		t.minWidth[n] = w
+	}
+	return t
+}
+
+// PriorityCol sets the priority for shrinking column n by MaxWidth(); columns
+// with a lower priority are shrunk first. The default is 0.
+func (t *Table) PriorityCol(n, p int) *Table {
+	if t.checkN(n, "PriorityCol") {
+		t.priority[n] = p
+	}
+	return t
+}
+
 // AlignCol sets the alignment for column n.
 //
-// The default is right-aligned for numbers, and left-aligned for everything
-// else.
+// The default is right-aligned for numbers and time.Duration, and left-aligned
+// for everything else.
+//
+// Decimal aligns the cells on the first decimal separator, so that numbers
+// with a different precision line up; cells without a separator are aligned
+// as integers. This only applies to Horizontal(); other formats right-align
+// the column.
 func (t *Table) AlignCol(n int, a Align) *Table {
 	if t.checkN(n, "AlignCol") {
+		recalc := a == Decimal || t.align[n] == Decimal
 		t.align[n] = a
+		if recalc {
+			t.recalcWidth(n)
+		}
+	}
+	return t
+}
+
+// DecimalSep sets the decimal separator for columns aligned with Decimal. The
+// default is '.'.
+func (t *Table) DecimalSep(sep rune) *Table {
+	t.decSep = sep
+	for i, a := range t.align {
+		if a == Decimal {
+			t.recalcWidth(i)
+		}
 	}
 	return t
 }
@@ -119,7 +295,80 @@ func (t *Table) FormatColFunc(n int, p FormatAsFunc) *Table {
 	return t
 }
 
+// WrapCol word-wraps the cells in column n to be at most width columns wide.
+//
+// Wrapped cells are printed on multiple lines, and any colours set with escape
+// sequences are continued on the next line. Use 0 to disable wrapping, which
+// is the default.
+func (t *Table) WrapCol(n, width int) *Table {
+	if t.checkN(n, "WrapCol") {
+		t.wrap[n], t.trunc[n] = width, 0
+		t.recalcWidth(n)
+	}
+	return t
+}
+
+// TruncateCol truncates the cells in column n to be at most width columns wide.
+//
+// The removed text is replaced with the Ellipsis(); the at parameter controls
+// where the text is removed. Escape sequences are never removed, so colours
+// will still be reset. Use 0 to disable truncating, which is the default.
+//
+// This will disable WrapCol().
+func (t *Table) TruncateCol(n, width int, at Truncate) *Table {
+	if t.checkN(n, "TruncateCol") {
+		t.trunc[n], t.truncAt[n], t.wrap[n] = width, at, 0
+		t.recalcWidth(n)
+	}
+	return t
+}
+
+// Recalculate the width of column n, in case rows were already added.
+func (t *Table) recalcWidth(n int) {
+	t.widths[n] = textWidth(t.header[n])
+	t.intWidth[n], t.fracWidth[n] = 0, 0
+	for _, r := range t.rows {
+		if n < len(r) {
+			t.growWidth(n, r[n])
+		}
+	}
+}
+
+// growWidth grows the width of column n if the cell s doesn't fit.
+func (t *Table) growWidth(n int, s string) {
+	if l := t.cellWidth(n, s); l > t.widths[n] {
+		t.widths[n] = l
+	}
+	if t.align[n] != Decimal {
+		return
+	}
+	i, f := t.decimalWidths(s)
+	if i > t.intWidth[n] {
+		t.intWidth[n] = i
+	}
+	if f > t.fracWidth[n] {
+		t.fracWidth[n] = f
+	}
+	if l := t.intWidth[n] + t.fracWidth[n]; l > t.widths[n] {
+		t.widths[n] = l
+	}
+}
+
+// decimalWidths gets the display width of the integer part and the fraction of
+// s, including the decimal separator in the fraction.
+func (t Table) decimalWidths(s string) (int, int) {
+	i := strings.IndexRune(s, t.decSep)
+	if i == -1 {
+		return textWidth(s), 0
+	}
+	return textWidth(s[:i]), textWidth(s[i:])
+}
+
 func (t *Table) checkN(n int, f string) bool {
+	if n < 0 {
+		t.err = fmt.Errorf("%s: cannot set negative column %d", f, n)
+		return false
+	}
 	if n > len(t.header)-1 {
 		t.err = fmt.Errorf("%s: cannot set column %d as there are only %d columns", f, n, len(t.header))
 		return false
@@ -144,6 +393,17 @@ func (t *Table) Header(show bool, header ...string) *Table {
 			t.printAs = make([]FormatAs, len(header))
 			t.printAsF = make([]FormatAsFunc, len(header))
 			t.align = make([]Align, len(header))
+			t.minWidth = make([]int, len(header))
+			t.priority = make([]int, len(header))
+			t.wrap = make([]int, len(header))
+			t.trunc = make([]int, len(header))
+			t.truncAt = make([]Truncate, len(header))
+			t.styleCol = make([]Style, len(header))
+			t.styleHeader = make([]Style, len(header))
+			t.heatmap = make([]*Heatmap, len(header))
+			t.bars = make([]int, len(header))
+			t.intWidth = make([]int, len(header))
+			t.fracWidth = make([]int, len(header))
 			for i := range header {
 				t.printAs[i] = "%v"
 			}
@@ -154,6 +414,17 @@ func (t *Table) Header(show bool, header ...string) *Table {
 			t.printAs = append(t.printAs, make([]FormatAs, grow)...)
 			t.printAsF = append(t.printAsF, make([]FormatAsFunc, grow)...)
 			t.align = append(t.align, make([]Align, grow)...)
+			t.minWidth = append(t.minWidth, make([]int, grow)...)
+			t.priority = append(t.priority, make([]int, grow)...)
+			t.wrap = append(t.wrap, make([]int, grow)...)
+			t.trunc = append(t.trunc, make([]int, grow)...)
+			t.truncAt = append(t.truncAt, make([]Truncate, grow)...)
+			t.styleCol = append(t.styleCol, make([]Style, grow)...)
+			t.styleHeader = append(t.styleHeader, make([]Style, grow)...)
+			t.heatmap = append(t.heatmap, make([]*Heatmap, grow)...)
+			t.bars = append(t.bars, make([]int, grow)...)
+			t.intWidth = append(t.intWidth, make([]int, grow)...)
+			t.fracWidth = append(t.fracWidth, make([]int, grow)...)
 			t.printAs[2] = "%v"
 			t.widths[2] = 4
 			//for i:=grow; i++ {
@@ -166,7 +437,7 @@ func (t *Table) Header(show bool, header ...string) *Table {
 
 		t.header = header
 		for i := range header {
-			if l := termtext.Width(header[i]); l > t.widths[i] {
+			if l := textWidth(header[i]); l > t.widths[i] {
 				t.widths[i] = l
 			}
 		}
@@ -204,11 +475,16 @@ func (t *Table) Width() int {
 func (t *Table) Grow(n int) {
 	if len(t.rows) == 0 {
 		t.rows = make([][]string, 0, n)
+		t.raw = make([][]any, 0, n)
 		return
 	}
 	r := make([][]string, len(t.rows), cap(t.rows)+n)
 	copy(r, t.rows)
 	t.rows = r
+
+	raw := make([][]any, len(t.raw), cap(t.raw)+n)
+	copy(raw, t.raw)
+	t.raw = raw
 }
 
 // Rows adds multiple rows; the number of values should be an exact multitude of
@@ -299,28 +575,71 @@ func (t *Table) Row(r ...any) *Table {
 
 	row := make([]string, len(r))
 	for i := range r {
-		f := t.printAsF[i]
-		if f != nil {
-			row[i] = f(r[i])
-			if row[i] == "\x00" {
-				row[i] = fmt.Sprintf(string(t.printAs[i]), r[i])
-			}
-		} else {
-			row[i] = fmt.Sprintf(string(t.printAs[i]), r[i])
-		}
-		if l := termtext.Width(row[i]); l > t.widths[i] {
-			t.widths[i] = l
-		}
+		row[i] = t.formatCell(i, r[i])
+		t.growWidth(i, row[i])
 	}
 	t.rows = append(t.rows, row)
+	t.raw = append(t.raw, append(make([]any, 0, len(r)), r...))
 	return t
 }
 
+// formatCell formats v with the FormatCol() and FormatColFunc() for column col.
+func (t Table) formatCell(col int, v any) string {
+	if f := t.printAsF[col]; f != nil {
+		if s := f(v); s != "\x00" {
+			return s
+		}
+	}
+	return fmt.Sprintf(string(t.printAs[col]), v)
+}
+
+// cellWidth gets the display width of a cell.
+func (t Table) cellWidth(col int, s string) int {
+	if w := t.trunc[col]; w > 0 {
+		if l := textWidth(s); l < w {
+			return l
+		}
+		return w
+	}
+
+	w := t.wrap[col]
+	if w == 0 {
+		return textWidth(s)
+	}
+
+	var l int
+	for _, line := range splitLines(s) {
+		lines := []string{line}
+		if termtext.Width(line) > w {
+			lines = wrapText(line, w)
+		}
+		for _, line := range lines {
+			if ll := termtext.Width(line); ll > l {
+				l = ll
+			}
+		}
+	}
+	return l
+}
+
+// maxCellWidth gets the maximum width for cells in column col, which is w unless
+// WrapCol() or TruncateCol() is set to something lower.
+func (t Table) maxCellWidth(col, w int) int {
+	if m := t.wrap[col]; m > 0 && m < w {
+		return m
+	}
+	if m := t.trunc[col]; m > 0 && m < w {
+		return m
+	}
+	return w
+}
+
 func isNumber(i any) bool {
 	switch i.(type) {
 	default:
 		return false
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, complex64, complex128:
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, complex64, complex128,
+		time.Duration:
 		return true
 	}
 }
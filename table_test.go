@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"strings"
 	"testing"
+	"time"
 )
 
 func trim(s string) string {
@@ -26,6 +28,16 @@ func test(t *testing.T, f func(io.Writer), want string) {
 	}
 }
 
+// noErr wraps f for use with test(), failing the test if f returns an error.
+func noErr(t *testing.T, f func(io.Writer) error) func(io.Writer) {
+	return func(w io.Writer) {
+		t.Helper()
+		if err := f(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 func errorContains(have error, want string) bool {
 	if have == nil {
 		return want == ""
@@ -246,6 +258,45 @@ func TestAlign(t *testing.T) {
 	`)
 }
 
+func TestAlignDecimal(t *testing.T) {
+	tbl := New("n", "after", "sep").Close(CloseLeft|CloseRight).
+		AlignCol(0, Decimal).
+		Rows(
+			1.5, 1.5, "1,5",
+			12.25, 12.25, "12,25",
+			100, 100, "100",
+			-3.125, -3.125, "-3,125").
+		AlignCol(1, Decimal)
+
+	test(t, tbl.Horizontal, `
+		│     n     │   after   │   sep    │
+		├───────────┼───────────┼──────────┤
+		│    1.5    │    1.5    │  1,5     │
+		│   12.25   │   12.25   │  12,25   │
+		│  100      │  100      │  100     │
+		│   -3.125  │   -3.125  │  -3,125  │
+	`)
+
+	tbl.DecimalSep(',').AlignCol(2, Decimal).AlignCol(1, Right)
+	test(t, tbl.Horizontal, `
+		│    n     │  after   │    sep    │
+		├──────────┼──────────┼───────────┤
+		│     1.5  │     1.5  │    1,5    │
+		│   12.25  │   12.25  │   12,25   │
+		│     100  │     100  │  100      │
+		│  -3.125  │  -3.125  │   -3,125  │
+	`)
+
+	test(t, tbl.Markdown, `
+		|      n |  after |     sep |
+		| -----: | -----: | ------: |
+		|    1.5 |    1.5 |     1,5 |
+		|  12.25 |  12.25 |   12,25 |
+		|    100 |    100 |     100 |
+		| -3.125 | -3.125 |  -3,125 |
+	`)
+}
+
 func TestFormatAs(t *testing.T) {
 	tbl := New("s").Close(CloseLeft|CloseRight).FormatCol(0, "%q").Row("asd")
 
@@ -275,9 +326,524 @@ func TestFormatAsFunc(t *testing.T) {
 		Row(1.5, 1.5, 0.8, 1.4, 1.6, 1234, uint64(123456789), 12341.123131, int16(-9999))
 
 	test(t, tbl.Horizontal, `
-		│   f1   │     f2     │   f3   │  f4  │  f5  │   n1    │      n2       │    n3    │    n4    │
-		├────────┼────────────┼────────┼──────┼──────┼─────────┼───────────────┼──────────┼──────────┤
-		│  1.50  │  1.500000  │  .800  │   1  │   2  │  1,234  │  123,456,789  │  12,341  │  -9,999  │
+		│   f1   │     f2     │   f3    │  f4  │  f5  │   n1    │      n2       │    n3    │    n4    │
+		├────────┼────────────┼─────────┼──────┼──────┼─────────┼───────────────┼──────────┼──────────┤
+		│  1.50  │  1.500000  │  0.800  │   1  │   2  │  1,234  │  123,456,789  │  12,341  │  -9,999  │
+	`)
+
+}
+
+func TestFormatAsFloat(t *testing.T) {
+	tests := []struct {
+		in   any
+		perc int
+		want string
+	}{
+		{0.8, 3, "0.800"},
+		{0.0, 1, "0.0"},
+		{0.4, 0, "0"},
+		{0.999, 2, "1.00"},
+		{float32(1.5), 1, "1.5"},
+		{-0.5, 2, "-0.50"},
+		{-5.0, 1, "-5.0"},
+		{5, 1, "\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			if have := FormatAsFloat(tt.perc)(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAsBytes(t *testing.T) {
+	tests := []struct {
+		base      Bytes
+		precision int
+		in        any
+		want      string
+	}{
+		{BytesIEC, 1, 0, "0 B"},
+		{BytesIEC, 1, 1023, "1023 B"},
+		{BytesIEC, 1, int16(1024), "1.0 KiB"},
+		{BytesIEC, 1, uint32(1536), "1.5 KiB"},
+		{BytesIEC, 2, int64(5 << 30), "5.00 GiB"},
+		{BytesIEC, 0, uint64(1<<64 - 1), "16 EiB"},
+		{BytesIEC, 1, 1048575, "1.0 MiB"},
+		{BytesIEC, 1, -2048, "-2.0 KiB"},
+		{BytesIEC, 1, 1.5e30, "1240770.9 YiB"},
+		{BytesSI, 1, 999, "999 B"},
+		{BytesSI, 1, float32(1500), "1.5 kB"},
+		{BytesSI, 0, uint8(200), "200 B"},
+		{BytesSI, 1, 2_500_000, "2.5 MB"},
+		{BytesIEC | BytesAlign, 1, 1, "1 B  "},
+		{BytesIEC | BytesAlign, 1, 2048, "2.0 KiB"},
+		{BytesSI | BytesAlign, 1, 1, "1 B "},
+		{BytesSI | BytesAlign, 1, 2000, "2.0 kB"},
+		{BytesIEC, 1, "1024", "1.0 KiB"},
+		{BytesIEC, 1, []byte("1536"), "1.5 KiB"},
+		{BytesIEC, 1, "x", "\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			if have := FormatAsBytes(tt.base, tt.precision)(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+
+	tbl := New("File", "Size", "Owner").
+		FormatColFunc(1, FormatAsBytes(BytesIEC|BytesAlign, 1)).
+		Rows(
+			"a", 100, "root",
+			"b", 20_000, "root",
+			"c", 3_000_000, "root")
+	test(t, tbl.Horizontal, `
+		  File  │    Size    │  Owner
+		────────┼────────────┼─────────
+		  a     │   100 B    │  root
+		  b     │  19.5 KiB  │  root
+		  c     │   2.9 MiB  │  root
 	`)
+}
+
+func TestFormatAsDuration(t *testing.T) {
+	tests := []struct {
+		in        any
+		precision int
+		want      string
+	}{
+		{time.Duration(0), 2, "0s"},
+		{12 * time.Nanosecond, 2, "12ns"},
+		{450*time.Microsecond + 400, 2, "450µs"},
+		{999*time.Microsecond + 600, 2, "1ms"},
+		{450 * time.Millisecond, 2, "450ms"},
+		{92*time.Second + 600*time.Millisecond, 2, "1m33s"},
+		{92*time.Second + 600*time.Millisecond, 0, "1m32s600ms"},
+		{59*time.Second + 600*time.Millisecond, 1, "1m"},
+		{time.Hour + 5*time.Second, 3, "1h5s"},
+		{time.Hour + 5*time.Second, 2, "1h"},
+		{52*time.Hour + 20*time.Minute, 2, "2d4h"},
+		{-90 * time.Second, 2, "-1m30s"},
+		{-5 * time.Microsecond, 2, "-5µs"},
+		{time.Duration(math.MinInt64), 2, "-106751d23h"},
+		{time.Duration(math.MinInt64), 0, "-106751d23h47m16s854ms"},
+		{5, 2, "\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			if have := FormatAsDuration(tt.precision)(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAsRelTime(t *testing.T) {
+	now := time.Date(2021, 6, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{now, "now"},
+		{now.Add(-500 * time.Millisecond), "now"},
+		{now.Add(-30 * time.Second), "30s ago"},
+		{now.Add(-3*time.Hour - 59*time.Minute), "3h ago"},
+		{now.Add(48 * time.Hour), "in 2d"},
+		{now.Add(-800 * 24 * time.Hour), "2y ago"},
+		{time.Time{}, ""},
+		{"x", "\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			if have := FormatAsRelTime(now)(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAsTime(t *testing.T) {
+	tm := time.Date(2021, 6, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   any
+		loc  *time.Location
+		want string
+	}{
+		{tm, nil, "2021-06-18 12:00 UTC"},
+		{tm, time.FixedZone("X", 3600), "2021-06-18 13:00 X"},
+		{time.Time{}, nil, ""},
+		{"x", nil, "\x00"},
+	}
 
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			if have := FormatAsTime("2006-01-02 15:04 MST", tt.loc)(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAsSparkline(t *testing.T) {
+	tests := []struct {
+		in    any
+		width int
+		want  string
+	}{
+		{[]float64{}, 0, ""},
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8}, 0, "▁▂▃▄▅▆▇█"},
+		{[]float64{2, 2}, 0, "▁▁"},
+		{[]float64{0, math.NaN(), 10}, 0, "▁ █"},
+		{[]float64{1, 3, 5, 7, 9, 11}, 3, "▁▅█"},
+		{[]float64{1, 3, 5, 7, 9}, 2, "▁█"},
+		{[]int{1, 2}, 0, "\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			if have := FormatAsSparkline(tt.width)(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAsBar(t *testing.T) {
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{0, ""},
+		{-1, ""},
+		{1, "▌"},
+		{uint8(5), "██▌"},
+		{7.3, "███▋"},
+		{10, "█████"},
+		{20.0, "█████"},
+		{"5", "\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			if have := FormatAsBar(10, 5)(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxWidth(t *testing.T) {
+	newTable := func() *Table {
+		return New("Name", "Description", "N").Close(CloseAll).Rows(
+			"James Holden", "Tilting windmills", 1,
+			"Amos Burton", "\x1b[31mSpecific people skills\x1b[0m", 2)
+	}
+
+	t.Run("fits", func(t *testing.T) {
+		test(t, newTable().MaxWidth(60).Horizontal, `
+			┌────────────────┬──────────────────────────┬─────┐
+			│      Name      │       Description        │  N  │
+			├────────────────┼──────────────────────────┼─────┤
+			│  James Holden  │  Tilting windmills       │  1  │
+			│  Amos Burton   │  `+"\x1b[31mSpecific people skills\x1b[0m"+`  │  2  │
+			└────────────────┴──────────────────────────┴─────┘
+		`)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		test(t, newTable().MaxWidth(40).Horizontal, `
+			┌───────────────┬────────────────┬─────┐
+			│     Name      │  Description   │  N  │
+			├───────────────┼────────────────┼─────┤
+			│  James Hold…  │  Tilting win…  │  1  │
+			│  Amos Burton  │  `+"\x1b[31mSpecific pe…\x1b[0m"+`  │  2  │
+			└───────────────┴────────────────┴─────┘
+		`)
+	})
+
+	t.Run("wrap", func(t *testing.T) {
+		test(t, newTable().MaxWidth(40).Overflow(OverflowWrap).Horizontal, `
+			┌───────────────┬────────────────┬─────┐
+			│     Name      │  Description   │  N  │
+			├───────────────┼────────────────┼─────┤
+			│  James        │  Tilting       │  1  │
+			│  Holden       │  windmills     │     │
+			│  Amos Burton  │  `+"\x1b[31mSpecific\x1b[0m"+`      │  2  │
+			│               │  `+"\x1b[31mpeople\x1b[0m"+`        │     │
+			│               │  `+"\x1b[31mskills\x1b[0m"+`        │     │
+			└───────────────┴────────────────┴─────┘
+		`)
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		test(t, newTable().MaxWidth(30).PriorityCol(0, 1).MinWidthCol(1, 5).Horizontal, `
+			┌────────────┬─────────┬─────┐
+			│    Name    │  Desc…  │  N  │
+			├────────────┼─────────┼─────┤
+			│  James H…  │  Tilt…  │  1  │
+			│  Amos Bu…  │  `+"\x1b[31mSpec…\x1b[0m"+`  │  2  │
+			└────────────┴─────────┴─────┘
+		`)
+	})
+
+	t.Run("too small", func(t *testing.T) {
+		test(t, newTable().MaxWidth(1).Close(0).Horizontal, `
+			  Na…  │  De…  │  N
+			───────┼───────┼─────
+			  Ja…  │  Ti…  │  1
+			  Am…  │  `+"\x1b[31mSp…\x1b[0m"+`  │  2
+		`)
+	})
+}
+
+func TestWrapCol(t *testing.T) {
+	tbl := New("Name", "Speciality", "N").Close(CloseAll).
+		Rows(
+			"James Holden", "Tilting windmills", 1,
+			"Amos Burton", "\x1b[31mSpecific people skills\x1b[0m", 2).
+		WrapCol(1, 12)
+
+	test(t, tbl.Horizontal, `
+		┌────────────────┬──────────────┬─────┐
+		│      Name      │  Speciality  │  N  │
+		├────────────────┼──────────────┼─────┤
+		│  James Holden  │  Tilting     │  1  │
+		│                │  windmills   │     │
+		│  Amos Burton   │  `+"\x1b[31mSpecific\x1b[0m"+`    │  2  │
+		│                │  `+"\x1b[31mpeople\x1b[0m"+`      │     │
+		│                │  `+"\x1b[31mskills\x1b[0m"+`      │     │
+		└────────────────┴──────────────┴─────┘
+	`)
+
+	test(t, tbl.Vertical, `
+		┌──────────────┬────────────────┐
+		│  Name        │  James Holden  │
+		│  Speciality  │  Tilting       │
+		│              │  windmills     │
+		│  N           │  1             │
+		├──────────────┼────────────────┤
+		│  Name        │  Amos Burton   │
+		│  Speciality  │  `+"\x1b[31mSpecific\x1b[0m"+`      │
+		│              │  `+"\x1b[31mpeople\x1b[0m"+`        │
+		│              │  `+"\x1b[31mskills\x1b[0m"+`        │
+		│  N           │  2             │
+		└──────────────┴────────────────┘
+	`)
+}
+
+func TestMultiLine(t *testing.T) {
+	tbl := New("Name", "Multi\nline", "N").Close(CloseAll).
+		Rows(
+			"James Holden", "Tilting\r\nwindmills", 1,
+			"Amos Burton", "\x1b[31mSpecific people\nskills\x1b[0m", 2)
+
+	test(t, tbl.Horizontal, `
+		┌────────────────┬───────────────────┬─────┐
+		│      Name      │       Multi       │  N  │
+		│                │       line        │     │
+		├────────────────┼───────────────────┼─────┤
+		│  James Holden  │  Tilting          │  1  │
+		│                │  windmills        │     │
+		│  Amos Burton   │  `+"\x1b[31mSpecific people\x1b[0m"+`  │  2  │
+		│                │  `+"\x1b[31mskills\x1b[0m"+`           │     │
+		└────────────────┴───────────────────┴─────┘
+	`)
+
+	test(t, tbl.Vertical, `
+		┌─────────┬───────────────────┐
+		│  Name   │  James Holden     │
+		│  Multi  │  Tilting          │
+		│  line   │  windmills        │
+		│  N      │  1                │
+		├─────────┼───────────────────┤
+		│  Name   │  Amos Burton      │
+		│  Multi  │  `+"\x1b[31mSpecific people\x1b[0m"+`  │
+		│  line   │  `+"\x1b[31mskills\x1b[0m"+`           │
+		│  N      │  2                │
+		└─────────┴───────────────────┘
+	`)
+
+	tbl.WrapCol(1, 10)
+	test(t, tbl.Horizontal, `
+		┌────────────────┬─────────────┬─────┐
+		│      Name      │    Multi    │  N  │
+		│                │    line     │     │
+		├────────────────┼─────────────┼─────┤
+		│  James Holden  │  Tilting    │  1  │
+		│                │  windmills  │     │
+		│  Amos Burton   │  `+"\x1b[31mSpecific\x1b[0m"+`   │  2  │
+		│                │  `+"\x1b[31mpeople\x1b[0m"+`     │     │
+		│                │  `+"\x1b[31mskills\x1b[0m"+`     │     │
+		└────────────────┴─────────────┴─────┘
+	`)
+}
+
+func TestTruncateCol(t *testing.T) {
+	tbl := New("Path", "URL").Close(CloseAll).
+		Rows(
+			"/home/martin/code/acidtab/table.go", "https://github.com/arp242/acidtab",
+			"/etc/passwd", "\x1b[34mhttps://example.com/some/long/path\x1b[0m").
+		TruncateCol(0, 15, TruncateMiddle).
+		TruncateCol(1, 20, TruncateEnd)
+
+	test(t, tbl.Horizontal, `
+		┌───────────────────┬────────────────────────┐
+		│       Path        │          URL           │
+		├───────────────────┼────────────────────────┤
+		│  /home/m…able.go  │  https://github.com/…  │
+		│  /etc/passwd      │  `+"\x1b[34mhttps://example.com…\x1b[0m"+`  │
+		└───────────────────┴────────────────────────┘
+	`)
+
+	tbl.Ellipsis("...").TruncateCol(0, 15, TruncateStart)
+	test(t, tbl.Vertical, `
+		┌────────┬────────────────────────┐
+		│  Path  │  ...tab/table.go       │
+		│  URL   │  https://github.co...  │
+		├────────┼────────────────────────┤
+		│  Path  │  /etc/passwd           │
+		│  URL   │  `+"\x1b[34mhttps://example.c...\x1b[0m"+`  │
+		└────────┴────────────────────────┘
+	`)
+}
+
+func TestTitle(t *testing.T) {
+	tbl := New("Name", "Job").
+		Title("Crew").
+		Caption("Some of the crew of the Rocinante").
+		AlignCaption(Left).
+		Rows(
+			"Holden", "Captain",
+			"Amos", "Mechanic")
+
+	test(t, tbl.Horizontal, `
+		        Crew
+		   Name   │    Job
+		──────────┼────────────
+		  Holden  │  Captain
+		  Amos    │  Mechanic
+		Some of the crew of
+		the Rocinante
+	`)
+
+	tbl.Close(CloseAll)
+	test(t, tbl.Horizontal, `
+		┌───────────────────────┐
+		│         Crew          │
+		├──────────┬────────────┤
+		│   Name   │    Job     │
+		├──────────┼────────────┤
+		│  Holden  │  Captain   │
+		│  Amos    │  Mechanic  │
+		├──────────┴────────────┤
+		│  Some of the crew of  │
+		│  the Rocinante        │
+		└───────────────────────┘
+	`)
+
+	tbl.AlignTitle(Right)
+	test(t, tbl.Vertical, `
+		┌─────────────────────┐
+		│               Crew  │
+		├────────┬────────────┤
+		│  Name  │  Holden    │
+		│  Job   │  Captain   │
+		├────────┼────────────┤
+		│  Name  │  Amos      │
+		│  Job   │  Mechanic  │
+		├────────┴────────────┤
+		│  Some of the crew   │
+		│  of the Rocinante   │
+		└─────────────────────┘
+	`)
+
+	tbl = New("Name", "Job").Close(CloseLeft).
+		Title("Crew").AlignTitle(Center).
+		Caption("end").AlignCaption(Right).
+		Row("Holden", "Captain")
+	test(t, tbl.Horizontal, `
+		        Crew
+		│   Name   │    Job
+		├──────────┼───────────
+		│  Holden  │  Captain
+		                  end
+	`)
+
+	// Shouldn't panic if the title doesn't fit.
+	have := new(bytes.Buffer)
+	New().Title("x").Horizontal(have)
+	if want := "x\n\n\n"; have.String() != want {
+		t.Errorf("\nhave: %q\nwant: %q", have.String(), want)
+	}
+
+	tbl = New("a").Row("b").Pad("").Title("🌎🌎")
+	test(t, tbl.Horizontal, `
+		🌎
+		🌎
+		a
+		─
+		b
+	`)
+	test(t, tbl.Vertical, `
+		🌎
+		🌎
+		a│b
+	`)
+}
+
+func TestSeparator(t *testing.T) {
+	tbl := New("Host", "Day", "Requests").
+		Row("a.example.com", "Mon", 42).
+		Row("a.example.com", "Tue", 13).
+		Separator().
+		Separator().
+		Row("b.example.com", "Mon", 7).
+		Row("b.example.com", "Tue", 21).
+		Separator()
+
+	test(t, tbl.Horizontal, `
+		      Host       │  Day  │  Requests
+		─────────────────┼───────┼────────────
+		  a.example.com  │  Mon  │        42
+		  a.example.com  │  Tue  │        13
+		─────────────────┼───────┼────────────
+		  b.example.com  │  Mon  │         7
+		  b.example.com  │  Tue  │        21
+	`)
+
+	tbl.SeparateRows(true)
+	test(t, tbl.Horizontal, `
+		      Host       │  Day  │  Requests
+		─────────────────┼───────┼────────────
+		  a.example.com  │  Mon  │        42
+		─────────────────┼───────┼────────────
+		  a.example.com  │  Tue  │        13
+		─────────────────┼───────┼────────────
+		  b.example.com  │  Mon  │         7
+		─────────────────┼───────┼────────────
+		  b.example.com  │  Tue  │        21
+	`)
+
+	tbl.SeparateRows(false).SortBy(SortKey{Col: 2})
+	test(t, tbl.Vertical, `
+		Host      │  a.example.com
+		Day       │  Tue
+		Requests  │  13
+		Host      │  a.example.com
+		Day       │  Mon
+		Requests  │  42
+		──────────┼───────────────
+		Host      │  b.example.com
+		Day       │  Mon
+		Requests  │  7
+		Host      │  b.example.com
+		Day       │  Tue
+		Requests  │  21
+	`)
 }
@@ -0,0 +1,30 @@
+//go:build linux
+
+package acidtab
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// TerminalWidth gets the width of the terminal f is connected to.
+//
+// It returns 0 if f is not a terminal or if the width can't be determined.
+func TerminalWidth(f *os.File) int {
+	var ws struct{ row, col, xpixel, ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(),
+		uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.col)
+}
+
+// isTerminal reports if f is a terminal.
+func isTerminal(f *os.File) bool {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(),
+		uintptr(syscall.TCGETS), uintptr(unsafe.Pointer(&t)))
+	return errno == 0
+}
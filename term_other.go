@@ -0,0 +1,20 @@
+//go:build !linux
+
+package acidtab
+
+import "os"
+
+// TerminalWidth gets the width of the terminal f is connected to.
+//
+// It returns 0 if f is not a terminal or if the width can't be determined. This
+// is only implemented on Linux, and always returns 0 on other systems.
+func TerminalWidth(f *os.File) int {
+	return 0
+}
+
+// isTerminal reports if f is a terminal; this only checks if it's a character
+// device on systems other than Linux.
+func isTerminal(f *os.File) bool {
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
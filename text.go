@@ -0,0 +1,294 @@
+package acidtab
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+	"zgo.at/termtext"
+)
+
+// stripEscapes removes all terminal escape sequences from s.
+func stripEscapes(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+
+	b := new(strings.Builder)
+	b.Grow(len(s))
+	for {
+		i := strings.IndexByte(s, '\x1b')
+		if i == -1 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		s = s[i+escapeLen(s[i:]):]
+	}
+	return b.String()
+}
+
+// stripSGR removes all SGR escape sequences (colours, bold, etc.) from s.
+func stripSGR(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+
+	b := new(strings.Builder)
+	b.Grow(len(s))
+	for {
+		i := strings.Index(s, "\x1b[")
+		if i == -1 {
+			b.WriteString(s)
+			break
+		}
+		l := escapeLen(s[i:])
+		b.WriteString(s[:i])
+		if s[i+l-1] != 'm' {
+			b.WriteString(s[i : i+l])
+		}
+		s = s[i+l:]
+	}
+	return b.String()
+}
+
+// escapeLen gets the length of the escape sequence at the start of s.
+//
+// This is a CSI sequence (ESC [, parameters, and a final byte in the @–~ range),
+// an OSC sequence (ESC ], terminated by BEL or ESC \, as used for hyperlinks),
+// or just ESC followed by a single character for anything else.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// textWidth gets the display width of the widest line in s.
+func textWidth(s string) int {
+	if !strings.Contains(s, "\n") {
+		return termtext.Width(s)
+	}
+	var w int
+	for _, line := range strings.Split(s, "\n") {
+		if l := termtext.Width(line); l > w {
+			w = l
+		}
+	}
+	return w
+}
+
+// splitLines splits s on newlines. Any active SGR escape sequences are carried
+// over to the next line.
+func splitLines(s string) []string {
+	if !strings.Contains(s, "\n") {
+		return []string{s}
+	}
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+	return carryEscapes(lines)
+}
+
+// segment is either a single grapheme cluster or an escape sequence.
+type segment struct {
+	s   string
+	w   int // Display width; always 0 for escapes.
+	esc bool
+}
+
+// segments splits s in to grapheme clusters and escape sequences.
+func segments(s string) []segment {
+	segs := make([]segment, 0, len(s))
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			l := escapeLen(s)
+			segs = append(segs, segment{s: s[:l], esc: true})
+			s = s[l:]
+			continue
+		}
+
+		i := strings.IndexByte(s, '\x1b')
+		if i == -1 {
+			i = len(s)
+		}
+		g := uniseg.NewGraphemes(s[:i])
+		for g.Next() {
+			c := g.Str()
+			segs = append(segs, segment{s: c, w: termtext.Width(c)})
+		}
+		s = s[i:]
+	}
+	return segs
+}
+
+// truncate s to be at most w columns wide, replacing the removed text with
+// ellipsis.
+//
+// Escape sequences in the removed text are kept, so that any colours that are
+// set are still reset.
+func truncate(s string, w int, ellipsis string, at Truncate) string {
+	if termtext.Width(s) <= w {
+		return s
+	}
+	ew := termtext.Width(ellipsis)
+	if ew > w {
+		ellipsis, ew = "", 0
+	}
+
+	/// Width to keep at the start and end.
+	var keepStart, keepEnd int
+	switch at {
+	default:
+		keepStart = w - ew
+	case TruncateStart:
+		keepEnd = w - ew
+	case TruncateMiddle:
+		keepEnd = (w - ew) / 2
+		keepStart = w - ew - keepEnd
+	}
+
+	/// Remove everything in segs[start:end], except escapes.
+	var (
+		segs       = segments(s)
+		start, end = 0, len(segs)
+	)
+	for l := 0; start < len(segs); start++ {
+		if !segs[start].esc {
+			if l+segs[start].w > keepStart {
+				break
+			}
+			l += segs[start].w
+		}
+	}
+	for l := 0; end > start; end-- {
+		if !segs[end-1].esc {
+			if l+segs[end-1].w > keepEnd {
+				break
+			}
+			l += segs[end-1].w
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i, sg := range segs {
+		if i == start {
+			b.WriteString(ellipsis)
+		}
+		if sg.esc || i < start || i >= end {
+			b.WriteString(sg.s)
+		}
+	}
+	return b.String()
+}
+
+// wrapText wraps s on multiple lines that are at most w columns wide.
+//
+// Lines are broken at spaces if possible; words longer than w are broken in the
+// middle. Any active SGR escape sequences are carried over to the next line.
+func wrapText(s string, w int) []string {
+	if w < 1 {
+		w = 1
+	}
+
+	var (
+		lines         []string
+		line          strings.Builder
+		lineW         int
+		word, space   []segment
+		wordW, spaceW int
+	)
+	addWord := func() {
+		if wordW > 0 {
+			if lineW > 0 && lineW+spaceW+wordW > w {
+				lines = append(lines, line.String())
+				line.Reset()
+				lineW = 0
+			}
+			if lineW > 0 {
+				for _, sp := range space {
+					line.WriteString(sp.s)
+				}
+				lineW += spaceW
+			}
+		}
+		for _, sg := range word {
+			if lineW > 0 && lineW+sg.w > w { /// Break words longer than w.
+				lines = append(lines, line.String())
+				line.Reset()
+				lineW = 0
+			}
+			line.WriteString(sg.s)
+			lineW += sg.w
+		}
+		word, wordW, space, spaceW = word[:0], 0, space[:0], 0
+	}
+
+	for _, sg := range segments(s) {
+		if !sg.esc && len(sg.s) == 1 && unicode.IsSpace(rune(sg.s[0])) {
+			if wordW > 0 {
+				addWord()
+			}
+			space, spaceW = append(space, sg), spaceW+sg.w
+			continue
+		}
+		word, wordW = append(word, sg), wordW+sg.w
+	}
+	addWord()
+	lines = append(lines, line.String())
+
+	return carryEscapes(lines)
+}
+
+// carryEscapes makes sure that every line starts with the SGR escape sequences
+// that were active at the end of the previous line, and that every line with
+// active SGR escape sequences at the end is reset.
+func carryEscapes(lines []string) []string {
+	var active []string
+	for i, l := range lines {
+		prefix := strings.Join(active, "")
+		for s := l; ; {
+			j := strings.IndexByte(s, '\x1b')
+			if j == -1 {
+				break
+			}
+			s = s[j:]
+			n := escapeLen(s)
+			if seq := s[:n]; n > 2 && seq[1] == '[' && seq[n-1] == 'm' {
+				if p := seq[2 : n-1]; p == "" || p == "0" {
+					active = active[:0]
+				} else {
+					active = append(active, seq)
+				}
+			}
+			s = s[n:]
+		}
+
+		lines[i] = prefix + l
+		if len(active) > 0 && i < len(lines)-1 {
+			lines[i] += "\x1b[0m"
+		}
+	}
+	return lines
+}
@@ -0,0 +1,112 @@
+package acidtab
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStripEscapes(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"asd", "asd"},
+		{"\x1b[1masd\x1b[0m", "asd"},
+		{"a\x1b[38;5;196ms\x1b[Kd\x1b", "asd"},
+		{"a\x1b[", "a"},
+		{"\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"\x1b]8;;http://x\alink\x1b]8;;\a", "link"},
+		{"a\x1b]8;;http://x", "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if have := stripEscapes(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripSGR(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"asd", "asd"},
+		{"\x1b[1masd\x1b[0m", "asd"},
+		{"a\x1b[38;5;196ms\x1b[Kd\x1b[m", "as\x1b[Kd"},
+		{"a\x1b[", "a\x1b["},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if have := stripSGR(tt.in); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		at   Truncate
+		want string
+	}{
+		{"", 0, 0, ""},
+		{"asd", 3, TruncateEnd, "asd"},
+		{"asdf", 3, TruncateEnd, "as…"},
+		{"asdf", 1, TruncateEnd, "…"},
+		{"asdf", 0, TruncateEnd, ""},
+		{"🌎🌎🌎", 4, TruncateEnd, "🌎…"},
+		{"🌎🌎🌎", 3, TruncateEnd, "🌎…"},
+		{"\x1b[31masdf\x1b[0m", 3, TruncateEnd, "\x1b[31mas…\x1b[0m"},
+		{"as\x1b[31mdf\x1b[0m", 3, TruncateEnd, "as\x1b[31m…\x1b[0m"},
+
+		{"asdf", 3, TruncateStart, "…df"},
+		{"🌎🌎🌎", 4, TruncateStart, "…🌎"},
+		{"\x1b[31masdf\x1b[0m", 3, TruncateStart, "\x1b[31m…df\x1b[0m"},
+
+		{"/home/martin/file.go", 15, TruncateMiddle, "/home/m…file.go"},
+		{"/home/martin/file.go", 14, TruncateMiddle, "/home/m…ile.go"},
+		{"as\x1b[31mdf\x1b[0mgh", 5, TruncateMiddle, "as\x1b[31m…\x1b[0mgh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%d-%d", tt.in, tt.w, tt.at), func(t *testing.T) {
+			if have := truncate(tt.in, tt.w, "…", tt.at); have != tt.want {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want []string
+	}{
+		{"", 5, []string{""}},
+		{"asd", 5, []string{"asd"}},
+		{"asd zxc", 5, []string{"asd", "zxc"}},
+		{"a b c d e", 3, []string{"a b", "c d", "e"}},
+		{"  asd  ", 5, []string{"asd"}},
+		{"asdfghjkl", 4, []string{"asdf", "ghjk", "l"}},
+		{"a asdfghjkl", 4, []string{"a", "asdf", "ghjk", "l"}},
+		{"🌎🌎🌎", 4, []string{"🌎🌎", "🌎"}},
+		{"\x1b[31masd zxc\x1b[0m", 3, []string{"\x1b[31masd\x1b[0m", "\x1b[31mzxc\x1b[0m"}},
+		{"\x1b[1ma \x1b[31mb c\x1b[0m", 3, []string{"\x1b[1ma \x1b[31mb\x1b[0m", "\x1b[1m\x1b[31mc\x1b[0m"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%d", tt.in, tt.w), func(t *testing.T) {
+			have := wrapText(tt.in, tt.w)
+			if fmt.Sprintf("%q", have) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("\nhave: %q\nwant: %q", have, tt.want)
+			}
+		})
+	}
+}
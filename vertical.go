@@ -2,6 +2,7 @@ package acidtab
 
 import (
 	"io"
+	"strings"
 
 	"zgo.at/termtext"
 )
@@ -10,83 +11,207 @@ import (
 //
 // Data is always left-aligned, and Header(false) has no effect.
 func (t Table) Vertical(w io.Writer) {
-	b := getWriter(w)
+	b := t.termWriter(w)
+	t.rows, t.widths = t.barRows()
+	t.heatRange = t.heatRanges()
 
 	// We calculate this data when rows are added for horizontal tables; need to
 	// do different width calculations for vertical tables.
 	var (
 		padWidth    = termtext.Width(t.pad)
-		alignHeader = make([]string, len(t.header))
+		headerLines = make([][]string, len(t.header)) /// Including pad and alignment.
 		headerWidth int
 		valueWidth  int
 	)
 	for i := range t.header {
-		if l := termtext.Width(t.header[i]); l > headerWidth {
+		if l := textWidth(t.header[i]); l > headerWidth {
 			headerWidth = l
 		}
 	}
 	for i := range t.header {
-		alignHeader[i] = fillBytes(' ', headerWidth-termtext.Width(t.header[i]))
+		headerLines[i] = splitLines(t.header[i])
+		for j, h := range headerLines[i] {
+			headerLines[i][j] = t.headerStyle.merge(t.styleHeader[i]).apply(h) + t.pad + fillBytes(' ', headerWidth-termtext.Width(h))
+		}
 	}
 	for _, w := range t.widths {
 		if w > valueWidth {
 			valueWidth = w
 		}
 	}
+	rows, sep, idx := t.bodyRows(t.sepRows || !t.sepRowsSet)
+	footer := t.footerRows()
+	extra := footer
+	if t.grouped {
+		extra = append(rows[:len(rows):len(rows)], footer...)
+	}
+	for _, r := range extra {
+		for i := range r {
+			if l := t.cellWidth(i, r[i]); l > valueWidth {
+				valueWidth = l
+			}
+		}
+	}
+	rows = append(rows[:len(rows):len(rows)], footer...)
 	var (
 		padStr    = fillRunes(t.borders.Line, padWidth)
 		valueStr  = fillRunes(t.borders.Line, valueWidth)
 		headerStr = fillRunes(t.borders.Line, headerWidth)
 	)
 
+	var (
+		boxed      = t.close&CloseAll == CloseAll
+		innerWidth = headerWidth + padWidth*2 + 1 + valueWidth
+		titleWidth = innerWidth
+		topCross   = t.borders.LineTop
+	)
+	if len(t.headings(0)) > 0 {
+		topCross = t.borders.Line
+	}
+	if !boxed {
+		if t.close&CloseLeft != 0 {
+			titleWidth += 1 + padWidth
+		}
+		if t.close&CloseRight != 0 {
+			titleWidth += 1 + padWidth
+		}
+	}
+	if titleWidth < 0 {
+		titleWidth = 0
+	}
+
 	/// Write the actual table.
-	if t.close&CloseTop != 0 {
+	switch {
+	case t.title != "" && boxed:
 		t.vertLine(b, padStr, headerStr, valueStr,
-			t.borders.LineTop, t.borders.TopLeft, t.borders.TopRight)
+			t.borders.Line, t.borders.TopLeft, t.borders.TopRight)
+		t.titleLines(b, t.title, t.titleAlign, titleWidth, boxed, boxed)
+		t.vertLine(b, padStr, headerStr, valueStr,
+			topCross, t.borders.BarRight, t.borders.BarLeft)
+	case t.title != "":
+		t.titleLines(b, t.title, t.titleAlign, titleWidth, boxed, boxed)
+		fallthrough
+	default:
+		if t.close&CloseTop != 0 {
+			t.vertLine(b, padStr, headerStr, valueStr,
+				topCross, t.borders.TopLeft, t.borders.TopRight)
+		}
 	}
 
-	for i := range t.rows {
-		if i > 0 {
+	var stripe int
+	for i := range rows {
+		if h := t.headings(i); len(h) > 0 {
+			if i > 0 {
+				t.vertLine(b, padStr, headerStr, valueStr,
+					t.borders.LineBottom, t.borders.BarRight, t.borders.BarLeft)
+			}
+			for _, hh := range h {
+				t.titleLines(b, hh, Left, innerWidth, t.close&CloseLeft != 0, t.close&CloseRight != 0)
+			}
+			t.vertLine(b, padStr, headerStr, valueStr,
+				t.borders.LineTop, t.borders.BarRight, t.borders.BarLeft)
+		} else if i > 0 && (i >= len(sep) || sep[i]) {
 			t.vertLine(b, padStr, headerStr, valueStr,
 				t.borders.Cross, t.borders.BarRight, t.borders.BarLeft)
 		}
+		styles := t.cellStyles(-1, -1)
+		if i < len(idx) && idx[i] >= 0 {
+			styles = t.cellStyles(idx[i], stripe)
+			stripe++
+		}
 		for j := range t.header {
-			/// Write header.
-			b.WriteString(t.prefix)
-			if t.close&CloseLeft != 0 {
-				b.WriteRune(t.borders.Bar)
-				b.WriteString(t.pad)
-			}
-			b.WriteString(t.header[j])
-			b.WriteString(t.pad)
-			b.WriteString(alignHeader[j])
-			b.WriteRune(t.borders.Bar)
-
-			/// Write data.
 			str := ""
-			if len(t.rows[i])-1 >= j { /// In case the header size changed.
-				str = t.rows[i][j]
+			if len(rows[i])-1 >= j { /// In case the header size changed.
+				str = rows[i][j]
 			}
+			lines := t.cellLines(j, str, t.maxCellWidth(j, valueWidth))
+			for l := 0; l < len(lines) || l < len(headerLines[j]); l++ {
+				/// Write header.
+				b.WriteString(t.prefix)
+				if t.close&CloseLeft != 0 {
+					b.WriteString(t.bar())
+					b.WriteString(t.pad)
+				}
+				if l < len(headerLines[j]) {
+					b.WriteString(headerLines[j][l])
+				} else {
+					b.WriteString(fillBytes(' ', headerWidth))
+					b.WriteString(t.pad)
+				}
+				b.WriteString(t.bar())
 
-			b.WriteString(t.pad)
-			b.WriteString(str)
-			if t.close&CloseRight != 0 {
-				b.WriteString(fillBytes(' ', valueWidth-termtext.Width(str)))
+				var line string
+				if l < len(lines) {
+					line = lines[l]
+				}
+
+				/// Write data.
 				b.WriteString(t.pad)
-				b.WriteRune(t.borders.Bar)
+				if t.close&CloseRight != 0 {
+					b.WriteString(styles[j].apply(line + fillBytes(' ', valueWidth-termtext.Width(line))))
+					b.WriteString(t.pad)
+					b.WriteString(t.bar())
+				} else {
+					b.WriteString(styles[j].apply(line))
+				}
+				b.WriteByte('\n')
 			}
-			b.WriteByte('\n')
 		}
 	}
 
+	/// Span rows added after the last row; these are printed before the footer
+	/// in the loop above if there is one.
+	bottomCross := t.borders.LineBottom
+	if h := t.headings(len(rows)); len(h) > 0 {
+		if len(rows) > 0 {
+			t.vertLine(b, padStr, headerStr, valueStr,
+				t.borders.LineBottom, t.borders.BarRight, t.borders.BarLeft)
+		}
+		for _, hh := range h {
+			t.titleLines(b, hh, Left, innerWidth, t.close&CloseLeft != 0, t.close&CloseRight != 0)
+		}
+		bottomCross = t.borders.Line
+	}
+
+	if t.caption != "" && boxed {
+		t.vertLine(b, padStr, headerStr, valueStr,
+			bottomCross, t.borders.BarRight, t.borders.BarLeft)
+		t.titleLines(b, t.caption, t.captionAlign, titleWidth, boxed, boxed)
+		t.vertLine(b, padStr, headerStr, valueStr,
+			t.borders.Line, t.borders.BottomLeft, t.borders.BottomRight)
+		return
+	}
 	if t.close&CloseBottom != 0 {
 		t.vertLine(b, padStr, headerStr, valueStr,
-			t.borders.LineBottom, t.borders.BottomLeft, t.borders.BottomRight)
+			bottomCross, t.borders.BottomLeft, t.borders.BottomRight)
+	}
+	if t.caption != "" {
+		t.titleLines(b, t.caption, t.captionAlign, titleWidth, boxed, boxed)
+	}
+}
+
+// headings gets the text of the span rows to print before row i.
+func (t Table) headings(i int) []string {
+	if t.grouped || i > len(t.rows) {
+		return nil
 	}
+	var h []string
+	for _, spans := range t.spanRowsAt(i) {
+		text := make([]string, 0, len(spans))
+		for _, s := range spans {
+			if s.Text != "" {
+				text = append(text, s.Text)
+			}
+		}
+		h = append(h, strings.Join(text, " "))
+	}
+	return h
 }
 
 func (t Table) vertLine(b writer, padStr, headerStr, valueStr string, cross, first, last rune) {
 	b.WriteString(t.prefix)
+	sgr := t.borderStyle.sgr()
+	b.WriteString(sgr)
 	if t.close&CloseLeft != 0 {
 		b.WriteRune(first)
 		b.WriteString(padStr)
@@ -100,5 +225,8 @@ func (t Table) vertLine(b writer, padStr, headerStr, valueStr string, cross, fir
 		b.WriteString(padStr)
 		b.WriteRune(last)
 	}
+	if sgr != "" {
+		b.WriteString("\x1b[0m")
+	}
 	b.WriteByte('\n')
 }